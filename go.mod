@@ -2,9 +2,9 @@ module git-metrics
 
 go 1.23.2
 
-require github.com/spf13/pflag v1.0.6
-
 require (
-	golang.org/x/sys v0.28.0 // indirect
-	golang.org/x/term v0.27.0 // indirect
+	github.com/spf13/pflag v1.0.6
+	golang.org/x/term v0.27.0
 )
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -1,18 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"runtime"
-	"sort"
+	"os/signal"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
+	"git-metrics/pkg/config"
+	"git-metrics/pkg/display"
 	"git-metrics/pkg/display/sections"
 	"git-metrics/pkg/git"
+	"git-metrics/pkg/metrics"
 	"git-metrics/pkg/models"
 	"git-metrics/pkg/progress"
 	"git-metrics/pkg/requirements"
@@ -21,20 +26,113 @@ import (
 
 var debug bool
 
-const (
-	UnknownValue = "Unknown"
-)
+// commandLineOnlyFlags select what a single run analyzes and are not read from the configuration
+var commandLineOnlyFlags = []string{"repository", "help", "version", "range", "pre-receive", "as-of", "until", "since", "save-snapshot"}
 
-func main() {
-	startTime := time.Now()
+// policyFlags set the limits checked by check
+var policyFlags = []string{"max-disk-size", "max-blob-size", "max-concern-level", "max-growth", "forbid-extensions"}
+
+// historyFlags apply to the analysis of the history but not to the objects of a range
+var historyFlags = []string{"as-of", "until", "since", "refs", "backtest", "max-growth", "sections", "skip", "save-snapshot"}
+
+// sectionList returns the names of the sections
+func sectionList(sections []models.Section) []string {
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		names[i] = string(section)
+	}
+	return names
+}
+
+// parseWindow parses the end of the analysis given with --as-of or --until and the start given with
+// --since. Without an end, the window ends now. The error is the complete message for the user.
+func parseWindow(asOfValue string, untilValue string, sinceValue string, now time.Time) (time.Time, time.Time, error) {
+	var asOf, since time.Time
+
+	// --until ends the window like --as-of, which also makes the estimates relative to it
+	asOfFlag := "as-of"
+	if untilValue != "" {
+		if asOfValue != "" {
+			return asOf, since, errors.New("--until and --as-of both set the end of the analysis. Use only one of them")
+		}
+		asOfFlag, asOfValue = "until", untilValue
+	}
+	if asOfValue != "" {
+		var err error
+		if _, asOf, err = utils.ParseDate(asOfValue); err != nil {
+			return asOf, since, fmt.Errorf("%v for --%s. Use a date like 2025-03-31 or a time like 2025-03-31T18:00:00Z", err, asOfFlag)
+		}
+		if asOf.After(now) {
+			return asOf, since, fmt.Errorf("--%s %s is in the future. Use a date up to today or omit it to analyze the current state", asOfFlag, asOfValue)
+		}
+	}
+
+	if sinceValue != "" {
+		var err error
+		if since, _, err = utils.ParseDate(sinceValue); err != nil {
+			return asOf, since, fmt.Errorf("%v for --since. Use a date like 2022-01-01 or a time like 2022-01-01T00:00:00Z", err)
+		}
+		end := asOf
+		if end.IsZero() {
+			end = now
+		}
+		if since.After(end) {
+			return asOf, since, fmt.Errorf("--since %s is after the end of the analysis. Use an earlier date or omit it to report the whole history", sinceValue)
+		}
+	}
+	return asOf, since, nil
+}
 
+func main() {
 	// Define flags with pflag for better help formatting
 	repositoryPath := pflag.StringP("repository", "r", ".", "Path to git repository")
 	showVersion := pflag.Bool("version", false, "Display version information and exit")
 	pflag.BoolVar(&debug, "debug", false, "Enable debug output")
 	noProgress := pflag.Bool("no-progress", false, "Disable progress indicators")
+	noCache := pflag.Bool("no-cache", false, "Do not read or update the history cache in the Git directory")
+	jobs := pflag.IntP("jobs", "j", 0, "Maximum number of collectors running at the same time (default: number of CPU cores)")
+	asOfValue := pflag.String("as-of", "", "Analyze the repository as it looked at this date, e.g. 2025-03-31 or 2025-03-31T18:00:00Z")
+	sinceValue := pflag.String("since", "", "Report only what happened from the month of this date on, e.g. 2022-01-01, while totals include the earlier history")
+	untilValue := pflag.String("until", "", "Report only what happened up to this date, e.g. 2024-12-31, the same as --as-of")
+	refPatterns := pflag.StringSlice("refs", nil, "Analyze only refs matching these patterns, e.g. refs/heads/*,refs/tags/*, and leave out refs matching patterns prefixed with ^ (default: all refs)")
+	intervalValue := pflag.String("interval", string(models.YearInterval), "Group growth, estimates, rates of changes and contributors by month, quarter or year")
+	forecastModelValue := pflag.String("forecast-model", string(models.LinearForecast), "Project the growth of the following periods with the linear, compound or regression model")
+	concernProfile := pflag.String("concern-profile", models.DefaultConcernProfile, "Evaluate the levels of concern with the thresholds of the default or the hosted profile")
+	concernThresholdValues := pflag.StringArray("concern-threshold", nil, "Change the thresholds of a metric, e.g. disk-size=2GB:40GB for on-road to concerning from 2 GB and concerning from 40 GB (repeatable)")
+	backtest := pflag.Bool("backtest", false, "Replay each forecast model at the end of every past period and report how far its forecasts were off")
+	sectionNames := pflag.StringSlice("sections", nil, "Collect and print only these sections in this order, e.g. growth,files,contributors, out of "+strings.Join(sectionList(models.SelectableSections), ",")+" (default: all)")
+	skippedSectionNames := pflag.StringSlice("skip", nil, "Collect and print all sections except these, e.g. rates,contributors")
+	forecastPeriods := pflag.Int("forecast-periods", metrics.DefaultLimits.ForecastPeriods, "Estimate the growth for this number of periods following the current one")
+	topExtensions := pflag.Int("top-extensions", metrics.DefaultLimits.FileExtensions, "List this number of file extensions with the largest on-disk size")
+	topGrowingExtensions := pflag.Int("top-growing-extensions", metrics.DefaultLimits.GrowingExtensions, "List this number of file extensions with the most on-disk growth per period, the text shows up to 3")
+	topFiles := pflag.Int("top-files", metrics.DefaultLimits.Files, "List this number of files with the largest on-disk size")
+	topContributors := pflag.Int("top-contributors", metrics.DefaultLimits.Contributors, "List this number of authors and committers with the most commits per period, the text shows up to 3")
+	directoryDepth := pflag.Int("directory-depth", metrics.DefaultLimits.DirectoryDepth, "List directories down to this number of levels below the root")
+	directoryThresholdValue := pflag.String("directory-threshold", fmt.Sprintf("%g%%", metrics.DefaultLimits.DirectoryThreshold), "List directories and files with more than this percentage of the on-disk size")
+	pathWidth := pflag.Int("path-width", sections.DefaultPathColumnWidth, "Shorten paths longer than this number of characters in the text output and list them in footnotes")
+	rangeValue := pflag.String("range", "", "Measure only the objects a range of commits adds, e.g. origin/main..main, instead of the history")
+	preReceive := pflag.Bool("pre-receive", false, "Measure only the objects the ref updates read from standard input add, in the format of pre-receive or pre-push hooks")
+	maxDiskSizeValue := pflag.String("max-disk-size", "", "With check, fail if the on-disk size of all objects, or of those a range adds, exceeds this size, e.g. 5GB")
+	maxBlobSizeValue := pflag.String("max-blob-size", "", "With check, fail if any blob is larger than this size, e.g. 100MB")
+	maxConcernLevelValue := pflag.String("max-concern-level", "", "With check, fail if any metric reached a higher level of concern than unconcerning or on-road (default with check and no other limit: on-road)")
+	maxGrowthValue := pflag.String("max-growth", "", "With check, fail if the on-disk size grew by more than this percentage in the last complete period, e.g. 25%")
+	forbiddenExtensions := pflag.StringSlice("forbid-extensions", nil, "With check, fail if any file has one of these extensions, e.g. .zip,.iso")
+	saveSnapshot := pflag.String("save-snapshot", "", "Also write the complete report as JSON to this file, to compare it with a later one using git-metrics diff")
+	timeout := pflag.Duration("timeout", 0, "Stop the analysis after this duration, e.g. 10m, and print the sections collected so far (default: no limit)")
+	outputFormat := pflag.String("format", display.TextFormat, "Output format: text or json")
 	showHelp := pflag.BoolP("help", "h", false, "Display this help message")
 
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: git-metrics [check] [options]\n")
+		fmt.Fprintf(os.Stderr, "       git-metrics diff <old snapshot> <new snapshot> [--format json]\n\n")
+		fmt.Fprintf(os.Stderr, "With check, the collected metrics are checked against the limits set by the --max-* and\n")
+		fmt.Fprintf(os.Stderr, "--forbid-extensions options and git-metrics exits with code 3 if the repository exceeds any.\n")
+		fmt.Fprintf(os.Stderr, "With diff, the changes between two reports saved with --save-snapshot are shown.\n\n")
+		fmt.Fprintf(os.Stderr, "Options other than --repository, --range, --pre-receive and the dates can also be set in\n")
+		fmt.Fprintf(os.Stderr, "the %s file of the repository, as gitmetrics.<option> in git config or as\n", config.FileName)
+		fmt.Fprintf(os.Stderr, "GIT_METRICS_<OPTION> environment variables, e.g. GIT_METRICS_TOP_FILES=20.\n\n")
+		pflag.PrintDefaults()
+	}
 	pflag.Parse()
 
 	// Show help and exit if help flag is set
@@ -49,332 +147,400 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Set progress visibility based on --no-progress flag and output destination
-	// Automatically disable progress when output is piped to a file or redirected
-	progress.ShowProgress = !*noProgress && utils.IsTerminal(os.Stdout)
+	check := false
+	switch arguments := pflag.Args(); {
+	case len(arguments) == 1 && arguments[0] == "check":
+		check = true
+	case len(arguments) == 3 && arguments[0] == "diff":
+		printSnapshotDiff(arguments[1], arguments[2], *outputFormat)
+		os.Exit(0)
+	case len(arguments) > 0 && arguments[0] == "diff":
+		fmt.Fprintln(os.Stderr, "Error: diff compares two snapshots. Use git-metrics diff old.json new.json with files saved by --save-snapshot")
+		os.Exit(1)
+	case len(arguments) > 0:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q. Use check, diff or no command, see --help\n", strings.Join(arguments, " "))
+		os.Exit(1)
+	}
 
 	if !requirements.CheckRequirements() {
 		fmt.Println("\nRequirements not met. Please install listed dependencies above.")
 		os.Exit(9)
 	}
 
-	// Get Git directory and change to repository directory
-	gitDir, err := git.GetGitDirectory(*repositoryPath)
+	// Options given on the command line take precedence over the configuration, also over its
+	// settings that select the same in another way
+	settings, err := config.Load(context.Background(), git.Repository{Path: *repositoryPath}, os.Environ())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: could not read the configuration: %v\n", err)
 		os.Exit(1)
 	}
+	sources := make(map[string]string)
+	for _, setting := range settings {
+		flag := pflag.Lookup(setting.Key)
+		if flag == nil || slices.Contains(commandLineOnlyFlags, setting.Key) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unknown setting %s in %s\n", setting.Key, setting.Source)
+			continue
+		}
+		// Limits apply to check and some options to the history only, unlike on the command line
+		// they are not an error in the configuration shared by all runs
+		if flag.Changed || pflag.CommandLine.Changed(config.Alternative(setting.Key)) || (!check && slices.Contains(policyFlags, setting.Key)) ||
+			((*rangeValue != "" || *preReceive) && slices.Contains(historyFlags, setting.Key)) {
+			continue
+		}
+		values := setting.Values
+		if _, repeatable := flag.Value.(pflag.SliceValue); !repeatable && len(values) > 0 {
+			values = values[len(values)-1:]
+		}
+		for _, value := range values {
+			if err := flag.Value.Set(value); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid value %q for %s in %s. Use a value --%s accepts, see --help\n", value, setting.Key, setting.Source, setting.Key)
+				os.Exit(1)
+			}
+		}
+		sources[setting.Key] = setting.Source
+	}
 
-	if err := os.Chdir(*repositoryPath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: could not change to repository directory: %v\n", err)
+	if *jobs < 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid number of jobs %d. Use --jobs with a positive number or omit it to use all CPU cores\n", *jobs)
 		os.Exit(1)
 	}
 
-	sections.DisplayRunInformation()
-
-	fmt.Println("\nREPOSITORY #############################################################################################################")
-	fmt.Println()
-
-	// Get Git directory last modified time
-	lastModified := UnknownValue
-	if info, err := os.Stat(gitDir); err == nil {
-		lastModified = info.ModTime().Format("Mon, 02 Jan 2006 15:04 MST")
+	if *timeout < 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid timeout %s. Use --timeout with a positive duration such as 30s or 10m, or omit it to run without a limit\n", *timeout)
+		os.Exit(1)
 	}
 
-	fmt.Printf("Git directory              %s\n", gitDir)
+	asOf, since, err := parseWindow(*asOfValue, *untilValue, *sinceValue, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Remote URL - only show if there is one
-	remoteOutput, err := git.RunGitCommand(debug, "remote", "get-url", "origin")
-	remote := ""
-	if err == nil && len(strings.TrimSpace(string(remoteOutput))) > 0 {
-		if progress.ShowProgress {
-			fmt.Printf("Remote                     ... fetching\n")
-		}
-		remote = strings.TrimSpace(string(remoteOutput))
-		if progress.ShowProgress {
-			fmt.Printf("\033[1A\033[2KRemote                     %s\n", remote)
-		} else {
-			fmt.Printf("Remote                     %s\n", remote)
-		}
+	refs, err := git.ParseRefScope(*refPatterns)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v for --refs. Use glob patterns like refs/heads/* or ^refs/pull/*\n", err)
+		os.Exit(1)
 	}
 
-	// Get fetch time and show last modified only if there's no recent fetch
-	recentFetch := git.GetLastFetchTime(gitDir)
-	if recentFetch == "" {
-		fmt.Printf("Last modified              %s\n", lastModified)
+	interval, err := models.ParseInterval(*intervalValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v for --interval. Use month, quarter or year\n", err)
+		os.Exit(1)
 	}
 
-	if recentFetch != "" {
-		fmt.Printf("Most recent fetch          %s\n", recentFetch)
+	forecastModel, err := models.ParseForecastModel(*forecastModelValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v for --forecast-model. Use linear, compound or regression\n", err)
+		os.Exit(1)
 	}
 
-	// Most recent commit
-	if progress.ShowProgress {
-		fmt.Printf("Most recent commit         ... fetching\n")
+	concernThresholds, err := models.ParseConcernProfile(*concernProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v for --concern-profile. Use %s\n", err, strings.Join(models.ConcernProfileNames(), " or "))
+		os.Exit(1)
 	}
-	lastHashOutput, err := git.RunGitCommand(debug, "rev-parse", "--short", "HEAD")
-	lastCommit := UnknownValue
-	if err == nil {
-		lastHash := strings.TrimSpace(string(lastHashOutput))
-		dateCommand := exec.Command("git", "show", "-s", "--format=%cD", lastHash)
-		commandOutput, err := dateCommand.Output()
-		if err == nil {
-			lastDate, _ := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", strings.TrimSpace(string(commandOutput)))
-			lastCommit = fmt.Sprintf("%s (%s)", lastDate.Format("Mon, 02 Jan 2006"), lastHash)
+	for _, value := range *concernThresholdValues {
+		metric, threshold, err := models.ParseConcernThreshold(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v for --concern-threshold. Use one of %s followed by the on-road to concerning and the concerning value, e.g. disk-size=2GB:40GB or commits=3000000:90000000\n", err, strings.Join(models.ConcernMetrics, ", "))
+			os.Exit(1)
 		}
-	}
-	if progress.ShowProgress {
-		fmt.Printf("\033[1A\033[2KMost recent commit         %s\n", lastCommit)
-	} else {
-		fmt.Printf("Most recent commit         %s\n", lastCommit)
+		concernThresholds[metric] = threshold
 	}
 
-	// First commit and age
-	if progress.ShowProgress {
-		fmt.Printf("First commit               ... fetching\n")
-	}
-	firstOutput, err := git.RunGitCommand(debug, "rev-list", "--max-parents=0", "HEAD", "--format=%cD")
-	firstCommit := UnknownValue
-	ageString := UnknownValue
-	var firstCommitTime time.Time
-	if err == nil {
-		lines := strings.Split(strings.TrimSpace(string(firstOutput)), "\n")
-		type commit struct {
-			hash string
-			date time.Time
+	if len(*sectionNames) > 0 && len(*skippedSectionNames) > 0 {
+		if source, configured := sources["sections"]; configured {
+			fmt.Fprintf(os.Stderr, "Error: sections in %s and skip in %s both select the sections. Use only one of them\n", source, sources["skip"])
+		} else {
+			fmt.Fprintln(os.Stderr, "Error: --sections and --skip both select the sections. Use only one of them")
 		}
-		var commits []commit
-		for i := 0; i < len(lines); i += 2 {
-			if i+1 >= len(lines) {
-				break
+		os.Exit(1)
+	}
+	parseSections := func(flag string, names []string) []models.Section {
+		var selected []models.Section
+		for _, name := range names {
+			section, err := models.ParseSection(strings.TrimSpace(name))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --%s. Use %s\n", err, flag, strings.Join(sectionList(models.SelectableSections), ", "))
+				os.Exit(1)
 			}
-			hash := strings.TrimPrefix(lines[i], "commit ")[:6]
-			if date, err := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", strings.TrimSpace(lines[i+1])); err == nil {
-				commits = append(commits, commit{hash: hash, date: date})
+			if !slices.Contains(selected, section) {
+				selected = append(selected, section)
 			}
 		}
-		if len(commits) > 0 {
-			sort.Slice(commits, func(i, j int) bool {
-				return commits[i].date.Before(commits[j].date)
-			})
-			first := commits[0]
-			firstCommitTime = first.date
-			firstCommit = fmt.Sprintf("%s (%s)", first.date.Format("Mon, 02 Jan 2006"), first.hash)
-			now := time.Now()
-			years, months, days := utils.CalculateYearsMonthsDays(first.date, now)
-			var parts []string
-			if years > 0 {
-				parts = append(parts, fmt.Sprintf("%d years", years))
-			}
-			if months > 0 {
-				parts = append(parts, fmt.Sprintf("%d months", months))
-			}
-			if days > 0 {
-				parts = append(parts, fmt.Sprintf("%d days", days))
+		return selected
+	}
+	selectedSections := parseSections("sections", *sectionNames)
+	if skipped := parseSections("skip", *skippedSectionNames); len(skipped) > 0 {
+		for _, section := range models.SelectableSections {
+			if !slices.Contains(skipped, section) {
+				selectedSections = append(selectedSections, section)
 			}
-			ageString = strings.Join(parts, " ")
+		}
+		if len(selectedSections) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --skip leaves out all sections. Keep at least one section or use --sections to select them")
+			os.Exit(1)
 		}
 	}
-	if progress.ShowProgress {
-		fmt.Printf("\033[1A\033[2KFirst commit               %s\n", firstCommit)
-	} else {
-		fmt.Printf("First commit               %s\n", firstCommit)
-	}
-
-	// If there are no commits, exit early
-	if firstCommit == UnknownValue {
-		fmt.Println("\n\nNo commits found in the repository.")
-		os.Exit(2)
-	}
-
-	fmt.Printf("Age                        %s\n", ageString)
-
-	// Display the section header before data collection
-	fmt.Println()
-	fmt.Println("HISTORIC & ESTIMATED GROWTH ############################################################################################")
-	fmt.Println()
-
-	// Print table headers before data collection (Year widened to 6 for ^* marker)
-	fmt.Println("Year          Commits          Δ     %   ○     Object size            Δ     %   ○    On-disk size            Δ     %   ○")
-	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-
-	// Calculate growth stats and totals
-	var previous models.GrowthStatistics
-	var totalStatistics models.GrowthStatistics
-
-	yearlyStatistics := make(map[int]models.GrowthStatistics)
-
-	// Start calculation with progress indicator (no newline before progress)
-	var beforePrevious models.GrowthStatistics
-	for year := firstCommitTime.Year(); year <= time.Now().Year(); year++ {
-		progress.StartProgress(year, previous, beforePrevious, startTime) // Start progress updates
-		if cumulativeStatistics, err := git.GetGrowthStats(year, previous, debug); err == nil {
-			totalStatistics = cumulativeStatistics
-			beforePrevious = previous
-			previous = cumulativeStatistics
-			yearlyStatistics[year] = cumulativeStatistics
-			progress.SetCurrentProgressStatistics(cumulativeStatistics, beforePrevious)
+	// Selecting the backtest is the same as requesting it, and requesting it adds it to the selection
+	if slices.Contains(selectedSections, models.BacktestSection) && len(*sectionNames) > 0 {
+		*backtest = true
+	} else if *backtest && len(selectedSections) > 0 && !slices.Contains(selectedSections, models.BacktestSection) {
+		if len(*skippedSectionNames) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: --backtest requests the backtest, which --skip leaves out. Use only one of them")
+			os.Exit(1)
 		}
+		selectedSections = append(selectedSections, models.BacktestSection)
 	}
-	progress.StopProgress() // Stop and clear progress line
-
-	// Start section spinner while computing author data and growth statistics
-	progress.StartSectionSpinner()
 
-	// Compute cumulative unique authors per year for historic growth
-	cumulativeAuthorsByYear, totalAuthors, authorsErr := git.GetCumulativeUniqueAuthorsByYear()
-	if authorsErr == nil {
-		// Inject authors into yearly statistics
-		for year, stats := range yearlyStatistics {
-			if authorsCount, ok := cumulativeAuthorsByYear[year]; ok {
-				stats.Authors = authorsCount
-				yearlyStatistics[year] = stats
+	var objects *git.Range
+	if *rangeValue != "" || *preReceive {
+		for _, flag := range historyFlags {
+			if pflag.CommandLine.Changed(flag) {
+				fmt.Fprintf(os.Stderr, "Error: --%s does not apply to the objects of a range. Omit it or analyze the history without --range and --pre-receive\n", flag)
+				os.Exit(1)
 			}
 		}
+		var parsed git.Range
+		switch {
+		case *rangeValue != "" && *preReceive:
+			fmt.Fprintln(os.Stderr, "Error: --range and --pre-receive both select the measured objects. Use only one of them")
+			os.Exit(1)
+		case *preReceive:
+			if parsed, err = git.ParseRefUpdates(os.Stdin); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --pre-receive. Pass lines of old object, new object and ref, as git passes them to pre-receive hooks\n", err)
+				os.Exit(1)
+			}
+		default:
+			if parsed, err = git.ParseRange(*rangeValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --range. Use two revisions like origin/main..main\n", err)
+				os.Exit(1)
+			}
+		}
+		objects = &parsed
 	}
 
-	// Save repository information with totals (including authors)
-	repositoryInformation := models.RepositoryInformation{
-		Remote:           remote,
-		LastCommit:       lastCommit,
-		FirstCommit:      firstCommit,
-		Age:              ageString,
-		FirstDate:        firstCommitTime,
-		TotalCommits:     totalStatistics.Commits,
-		TotalAuthors:     totalAuthors,
-		TotalTrees:       totalStatistics.Trees,
-		TotalBlobs:       totalStatistics.Blobs,
-		CompressedSize:   totalStatistics.Compressed,
-		UncompressedSize: totalStatistics.Uncompressed,
-	}
-
-	// Calculate and store delta, percentage, and delta percentage values
-	currentYear := time.Now().Year()
-	var previousCumulative models.GrowthStatistics
-	var previousDelta models.GrowthStatistics
-
-	// Process each year to calculate and store all derived values
-	for year := repositoryInformation.FirstDate.Year(); year <= currentYear; year++ {
-		if cumulative, ok := yearlyStatistics[year]; ok {
-			// Calculate delta values (year-over-year changes)
-			cumulative.AuthorsDelta = cumulative.Authors - previousCumulative.Authors
-			cumulative.CommitsDelta = cumulative.Commits - previousCumulative.Commits
-			cumulative.TreesDelta = cumulative.Trees - previousCumulative.Trees
-			cumulative.BlobsDelta = cumulative.Blobs - previousCumulative.Blobs
-			cumulative.CompressedDelta = cumulative.Compressed - previousCumulative.Compressed
-			cumulative.UncompressedDelta = cumulative.Uncompressed - previousCumulative.Uncompressed
-
-			// Calculate percentage of total
-			if repositoryInformation.TotalAuthors > 0 {
-				cumulative.AuthorsPercent = float64(cumulative.AuthorsDelta) / float64(repositoryInformation.TotalAuthors) * 100
+	var policy *models.Policy
+	if check {
+		policy = &models.Policy{}
+		// A size limit of zero would not limit anything, so only positive sizes are accepted
+		parseSizeLimit := func(value string) (int64, error) {
+			size, err := utils.ParseSize(value)
+			if err == nil && size <= 0 {
+				err = fmt.Errorf("%w %q", utils.ErrInvalidSize, value)
 			}
-			if repositoryInformation.TotalCommits > 0 {
-				cumulative.CommitsPercent = float64(cumulative.CommitsDelta) / float64(repositoryInformation.TotalCommits) * 100
+			return size, err
+		}
+		if *maxDiskSizeValue != "" {
+			if policy.MaxDiskSize, err = parseSizeLimit(*maxDiskSizeValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --max-disk-size. Use a positive size like 5GB or 500MB\n", err)
+				os.Exit(1)
 			}
-			if repositoryInformation.TotalTrees > 0 {
-				cumulative.TreesPercent = float64(cumulative.TreesDelta) / float64(repositoryInformation.TotalTrees) * 100
+		}
+		if *maxBlobSizeValue != "" {
+			if policy.MaxBlobSize, err = parseSizeLimit(*maxBlobSizeValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --max-blob-size. Use a positive size like 100MB\n", err)
+				os.Exit(1)
 			}
-			if repositoryInformation.TotalBlobs > 0 {
-				cumulative.BlobsPercent = float64(cumulative.BlobsDelta) / float64(repositoryInformation.TotalBlobs) * 100
+		}
+		if *maxConcernLevelValue != "" {
+			policy.MaxConcernLevel, err = models.ParseConcernLevel(*maxConcernLevelValue)
+			// Allowing the concerning level would not limit anything
+			if err == nil && policy.MaxConcernLevel == models.ConcerningConcernLevel {
+				err = fmt.Errorf("%w %q", models.ErrInvalidConcernLevel, *maxConcernLevelValue)
 			}
-			if repositoryInformation.CompressedSize > 0 {
-				cumulative.CompressedPercent = float64(cumulative.CompressedDelta) / float64(repositoryInformation.CompressedSize) * 100
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --max-concern-level. Use unconcerning or on-road\n", err)
+				os.Exit(1)
 			}
-			if repositoryInformation.UncompressedSize > 0 {
-				cumulative.UncompressedPercent = float64(cumulative.UncompressedDelta) / float64(repositoryInformation.UncompressedSize) * 100
+		}
+		if *maxGrowthValue != "" {
+			if policy.MaxGrowthPercent, err = models.ParsePercentage(*maxGrowthValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v for --max-growth. Use a positive percentage like 25 or 12.5%%\n", err)
+				os.Exit(1)
 			}
-
-			// Calculate delta percentage changes (Δ%)
-			if previousDelta.Year != 0 { // Skip first year
-				if previousDelta.AuthorsDelta > 0 {
-					cumulative.AuthorsDeltaPercent = float64(cumulative.AuthorsDelta-previousDelta.AuthorsDelta) / float64(previousDelta.AuthorsDelta) * 100
-				}
-				if previousDelta.CommitsDelta > 0 {
-					cumulative.CommitsDeltaPercent = float64(cumulative.CommitsDelta-previousDelta.CommitsDelta) / float64(previousDelta.CommitsDelta) * 100
-				}
-				if previousDelta.TreesDelta > 0 {
-					cumulative.TreesDeltaPercent = float64(cumulative.TreesDelta-previousDelta.TreesDelta) / float64(previousDelta.TreesDelta) * 100
-				}
-				if previousDelta.BlobsDelta > 0 {
-					cumulative.BlobsDeltaPercent = float64(cumulative.BlobsDelta-previousDelta.BlobsDelta) / float64(previousDelta.BlobsDelta) * 100
-				}
-				if previousDelta.CompressedDelta > 0 {
-					cumulative.CompressedDeltaPercent = float64(cumulative.CompressedDelta-previousDelta.CompressedDelta) / float64(previousDelta.CompressedDelta) * 100
-				}
-				if previousDelta.UncompressedDelta > 0 {
-					cumulative.UncompressedDeltaPercent = float64(cumulative.UncompressedDelta-previousDelta.UncompressedDelta) / float64(previousDelta.UncompressedDelta) * 100
-				}
+		}
+		for _, extension := range *forbiddenExtensions {
+			if extension = strings.TrimSpace(extension); extension != "" {
+				policy.ForbiddenExtensions = append(policy.ForbiddenExtensions, "."+strings.TrimPrefix(extension, "."))
 			}
-
-			// Store the updated statistics back in the map
-			yearlyStatistics[year] = cumulative
-
-			// Update for next iteration
-			previousCumulative = cumulative
-			previousDelta = cumulative
 		}
+		// Without any limit, check fails once a metric becomes concerning
+		if len(policy.Rules()) == 0 {
+			policy.MaxConcernLevel = models.OnRoadConcernLevel
+		}
+	} else if *maxDiskSizeValue != "" || *maxBlobSizeValue != "" || *maxConcernLevelValue != "" || *maxGrowthValue != "" || len(*forbiddenExtensions) > 0 {
+		fmt.Fprintln(os.Stderr, "Error: the --max-* and --forbid-extensions limits only apply to check. Run git-metrics check with them")
+		os.Exit(1)
 	}
 
-	// Display unified historic and estimated growth using the new function
-	progress.StopSectionSpinner()
-	sections.DisplayUnifiedGrowth(yearlyStatistics, repositoryInformation, firstCommitTime, recentFetch, lastModified)
+	limits := metrics.Limits{
+		FileExtensions:    *topExtensions,
+		GrowingExtensions: *topGrowingExtensions,
+		Files:             *topFiles,
+		Contributors:      *topContributors,
+		ForecastPeriods:   *forecastPeriods,
+		DirectoryDepth:    *directoryDepth,
+	}
+	for _, limit := range []struct {
+		flag  string
+		value int
+	}{
+		{"top-extensions", limits.FileExtensions},
+		{"top-growing-extensions", limits.GrowingExtensions},
+		{"top-files", limits.Files},
+		{"top-contributors", limits.Contributors},
+		{"forecast-periods", limits.ForecastPeriods},
+		{"directory-depth", limits.DirectoryDepth},
+		{"path-width", *pathWidth},
+	} {
+		if limit.value <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid number %d for --%s. Use a positive number or omit it to use the default\n", limit.value, limit.flag)
+			os.Exit(1)
+		}
+	}
+	if limits.DirectoryThreshold, err = models.ParsePercentage(*directoryThresholdValue); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v for --directory-threshold. Use a positive percentage like 1 or 0.5%%\n", err)
+		os.Exit(1)
+	}
+	sections.PathColumnWidth = *pathWidth
 
-	// 1. Largest file extensions
-	sections.PrintTopFileExtensions(previous.LargestFiles, repositoryInformation.TotalBlobs, repositoryInformation.CompressedSize)
+	renderer, err := display.NewRenderer(*outputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// 2. Largest file extensions on-disk size growth
-	sections.PrintFileExtensionGrowth(yearlyStatistics)
+	// Set progress visibility based on --no-progress flag and output destination
+	// Automatically disable progress when output is piped to a file or redirected
+	// and for machine-readable output that must not be mixed with progress lines
+	progress.ShowProgress = *outputFormat == display.TextFormat && !*noProgress && utils.IsTerminal(os.Stdout)
+
+	// Ctrl-C stops the analysis and prints the sections collected so far. Once the analysis is
+	// stopping, the default handling is restored so a second Ctrl-C exits immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
 
-	// Prepare largest files data once for sections 3 & 4
-	largestFiles := totalStatistics.LargestFiles
-	sort.Slice(largestFiles, func(i, j int) bool {
-		if largestFiles[i].CompressedSize != largestFiles[j].CompressedSize {
-			return largestFiles[i].CompressedSize > largestFiles[j].CompressedSize
-		}
-		return largestFiles[i].Path < largestFiles[j].Path
+	report, err := metrics.Analyze(ctx, metrics.Options{
+		RepositoryPath:    *repositoryPath,
+		Debug:             debug,
+		Cache:             !*noCache,
+		Jobs:              *jobs,
+		AsOf:              asOf,
+		Since:             since,
+		Refs:              refs,
+		Interval:          interval,
+		ForecastModel:     forecastModel,
+		Backtest:          *backtest,
+		Sections:          selectedSections,
+		Limits:            limits,
+		ConcernThresholds: concernThresholds,
+		Policy:            policy,
+		Range:             objects,
+		Observer:          renderer,
 	})
-
-	var totalFilesCompressedSize int64
-	for _, file := range largestFiles {
-		totalFilesCompressedSize += file.CompressedSize
+	if errors.Is(err, metrics.ErrNoCommits) {
+		fmt.Fprintln(os.Stderr, "\nNo commits found in the repository.")
+		os.Exit(2)
 	}
-
-	if len(largestFiles) > 10 {
-		largestFiles = largestFiles[:10]
+	if errors.Is(err, metrics.ErrNoMatchingRefs) {
+		fmt.Fprintf(os.Stderr, "Error: %v. Run git show-ref to list the refs of the repository or omit --refs to analyze all refs\n", err)
+		os.Exit(1)
+	}
+	if errors.Is(err, git.ErrUnknownRevision) {
+		fmt.Fprintf(os.Stderr, "Error: %v. Use revisions of the repository, such as branches, tags or object names\n", err)
+		os.Exit(1)
+	}
+	interrupted := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+	if err != nil && !interrupted {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	// 3. Largest directories
-	sections.PrintLargestDirectories(totalStatistics.LargestFiles, repositoryInformation.TotalBlobs, repositoryInformation.CompressedSize)
+	if report != nil {
+		if err := renderer.Finish(*report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not write report: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// 4. Largest files
-	sections.PrintLargestFiles(largestFiles, totalFilesCompressedSize, repositoryInformation.TotalBlobs, len(previous.LargestFiles))
+	// A snapshot of an interrupted run would make the next diff report missing sections as changes
+	if *saveSnapshot != "" && report != nil {
+		if report.Incomplete {
+			fmt.Fprintf(os.Stderr, "The snapshot %s was not saved as the analysis did not finish.\n", *saveSnapshot)
+		} else {
+			var snapshot bytes.Buffer
+			err := display.WriteJSONReport(&snapshot, *report)
+			if err == nil {
+				err = os.WriteFile(*saveSnapshot, snapshot.Bytes(), 0o644)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: could not save snapshot: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
 
-	// 5. Rate of changes analysis
-	progress.StartSectionSpinner()
-	ratesByYear, branchName, rateError := git.GetRateOfChanges()
-	progress.StopSectionSpinner()
-	if rateError == nil && len(ratesByYear) > 0 {
-		sections.PrintRateOfChangesSectionTitle()
-		sections.DisplayRateOfChanges(ratesByYear, branchName)
+	// Exit codes follow the conventions of shells for Ctrl-C and of the timeout command
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "Error: analysis did not finish within the timeout of %s\n", *timeout)
+		os.Exit(124)
+	}
+	if interrupted {
+		os.Exit(130)
+	}
+	if len(report.Violations) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: the repository does not comply with the policy, violations found: %d\n", len(report.Violations))
+		os.Exit(3)
 	}
+}
 
-	// 6 & 7. Authors and committers with most commits
-	progress.StartSectionSpinner()
-	topAuthorsByYear, totalAuthorsByYear, totalCommitsByYear, topCommittersByYear, totalCommittersByYear, allTimeAuthors, allTimeCommitters, contributorsError := git.GetTopCommitAuthors(3)
-	progress.StopSectionSpinner()
-	if contributorsError == nil && len(topAuthorsByYear) > 0 {
-		sections.PrintAuthorsSectionTitle()
-		sections.DisplayAuthorsSection(topAuthorsByYear, totalAuthorsByYear, totalCommitsByYear, allTimeAuthors)
+// printSnapshotDiff prints the changes between two reports saved with --save-snapshot and exits
+// on errors
+func printSnapshotDiff(oldPath string, newPath string, format string) {
+	// The snapshots were analyzed with their own options, those of the analysis do not apply
+	pflag.Visit(func(flag *pflag.Flag) {
+		if flag.Name != "format" {
+			fmt.Fprintf(os.Stderr, "Error: --%s does not apply to diff. Use it when saving the snapshots\n", flag.Name)
+			os.Exit(1)
+		}
+	})
 
-		sections.PrintCommittersSectionTitle()
-		sections.DisplayCommittersSection(topCommittersByYear, totalCommittersByYear, totalCommitsByYear, allTimeCommitters)
+	var reports []models.Report
+	for _, path := range []string{oldPath, newPath} {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not read snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		report, err := display.ReadJSONReport(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v in snapshot %s. Use reports saved with --save-snapshot or --format json by this version of git-metrics\n", err, path)
+			os.Exit(1)
+		}
+		if report.Incomplete {
+			fmt.Fprintf(os.Stderr, "Error: snapshot %s is incomplete as its analysis was interrupted. Use a complete snapshot\n", path)
+			os.Exit(1)
+		}
+		if report.Repository == nil {
+			fmt.Fprintf(os.Stderr, "Error: snapshot %s measures the objects of a range. Use a report of the history\n", path)
+			os.Exit(1)
+		}
+		reports = append(reports, report)
 	}
 
-	// Get memory statistics for final output
-	var memoryStatistics runtime.MemStats
-	runtime.ReadMemStats(&memoryStatistics)
-
-	fmt.Printf("\nFinished in %s with a memory footprint of %s.\n",
-		utils.FormatDuration(time.Since(startTime)),
-		strings.TrimSpace(utils.FormatSize(int64(memoryStatistics.Sys))))
+	if err := display.PrintReportDiff(format, sections.BuildReportDiff(reports[0], reports[1])); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
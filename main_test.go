@@ -75,3 +75,40 @@ func TestCalculateYearsMonthsDays(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWindow(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                string
+		asOf, until, since  string
+		wantAsOf, wantSince string
+		wantErr             bool
+	}{
+		{name: "Whole history"},
+		{name: "Since without an end", since: "2020-01-01", wantSince: "2020-01-01"},
+		{name: "Since and until", since: "2020-01-01", until: "2024-12-31", wantAsOf: "2024-12-31", wantSince: "2020-01-01"},
+		{name: "Since after as-of", since: "2025-01-01", asOf: "2024-12-31", wantErr: true},
+		{name: "Since in the future", since: "2025-07-01", wantErr: true},
+		{name: "Until and as-of", until: "2024-12-31", asOf: "2024-12-31", wantErr: true},
+		{name: "As-of in the future", asOf: "2025-07-01", wantErr: true},
+		{name: "Invalid since", since: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asOf, since, err := parseWindow(tt.asOf, tt.until, tt.since, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantSince != "" && since.Format(time.DateOnly) != tt.wantSince || tt.wantSince == "" && !since.IsZero() {
+				t.Errorf("parseWindow() since = %v, want %q", since, tt.wantSince)
+			}
+			if tt.wantAsOf != "" && asOf.Format(time.DateOnly) != tt.wantAsOf || tt.wantAsOf == "" && !asOf.IsZero() {
+				t.Errorf("parseWindow() as of = %v, want %q", asOf, tt.wantAsOf)
+			}
+		})
+	}
+}
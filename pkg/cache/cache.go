@@ -0,0 +1,90 @@
+// Package cache stores the history walked by an analysis in the Git directory, so later runs
+// only need to walk the commits and objects added since.
+package cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"git-metrics/pkg/git"
+	"git-metrics/pkg/models"
+)
+
+// FileName is the name of the cache file within the Git directory
+const FileName = "git-metrics.cache"
+
+// version is incremented whenever the cached data changes its layout or meaning
+const version = 4
+
+// ErrVersionMismatch is returned when the cache was written by an incompatible version
+var ErrVersionMismatch = errors.New("cache was written by an incompatible version of git-metrics")
+
+// cacheFile is the content of the cache file
+type cacheFile struct {
+	Version int
+	History git.History
+}
+
+// Load reads the history cached in gitDirectory
+func Load(gitDirectory string) (*git.History, error) {
+	file, err := os.Open(filepath.Join(gitDirectory, FileName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var content cacheFile
+	if err := gob.NewDecoder(file).Decode(&content); err != nil {
+		return nil, fmt.Errorf("could not read cache: %w", err)
+	}
+	if content.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	// Decoding leaves empty maps unset
+	history := git.NewHistory()
+	history.ObjectTips = content.History.ObjectTips
+	history.ContributorTips = content.History.ContributorTips
+	history.ObjectNames = content.History.ObjectNames
+	if history.ObjectNames == nil {
+		history.ObjectNames = git.NewObjectSet()
+	}
+	for month, objects := range content.History.Objects {
+		if objects.Files == nil {
+			objects.Files = make(map[string]models.FileInformation)
+		}
+		history.Objects[month] = objects
+	}
+	for month, contributors := range content.History.Contributors {
+		if contributors.Authors == nil {
+			contributors.Authors = make(map[string]int)
+		}
+		if contributors.Committers == nil {
+			contributors.Committers = make(map[string]int)
+		}
+		history.Contributors[month] = contributors
+	}
+	return history, nil
+}
+
+// Save writes history to the cache in gitDirectory. The cache is replaced atomically, so
+// concurrent runs never read a partially written cache.
+func Save(gitDirectory string, history *git.History) error {
+	temporaryFile, err := os.CreateTemp(gitDirectory, FileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporaryFile.Name())
+
+	if err := gob.NewEncoder(temporaryFile).Encode(cacheFile{Version: version, History: *history}); err != nil {
+		temporaryFile.Close()
+		return fmt.Errorf("could not write cache: %w", err)
+	}
+	if err := temporaryFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporaryFile.Name(), filepath.Join(gitDirectory, FileName))
+}
@@ -0,0 +1,153 @@
+// Package config reads the settings that change the defaults of git-metrics for a repository.
+// Settings are named like the command line options without the leading dashes and come from
+// three sources, each overriding the ones before it key by key:
+//
+//   - the .gitmetrics file at the top of the working tree, in the syntax of git config files
+//   - the gitmetrics.* keys of git config, such as gitmetrics.top-files in ~/.gitconfig
+//   - GIT_METRICS_* environment variables, such as GIT_METRICS_TOP_FILES
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-metrics/pkg/git"
+)
+
+// FileName is the name of the file with the settings shared by everyone working on a repository
+const FileName = ".gitmetrics"
+
+// section is the git config section of the settings
+const section = "gitmetrics"
+
+// environmentPrefix starts the names of the environment variables of the settings
+const environmentPrefix = "GIT_METRICS_"
+
+// alternativeKeys select the same in different ways, so a source setting one of them overrides
+// the other one of the sources before it
+var alternativeKeys = map[string]string{"sections": "skip", "skip": "sections"}
+
+// Alternative returns the key that selects the same as key in another way or "" if there is none
+func Alternative(key string) string {
+	return alternativeKeys[key]
+}
+
+// Setting is a setting together with the place it was read from
+type Setting struct {
+	Key    string   // Name of the option, such as top-files
+	Values []string // Values in the order they were set, several for repeatable options
+	Source string   // File or environment variable the values were read from
+}
+
+// Load returns the settings of the repository sorted by key. The environment is a list of
+// key=value pairs like the one os.Environ returns. No settings are loaded for paths that are
+// not a git repository, the analysis reports those.
+func Load(ctx context.Context, repository git.Repository, environment []string) ([]Setting, error) {
+	settings := make(map[string]Setting)
+
+	output, err := repository.RunGitCommand(ctx, "rev-parse", "--is-inside-work-tree")
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, nil
+	}
+
+	// Bare repositories have no working tree to read the file from
+	if strings.TrimSpace(string(output)) == "true" {
+		top, err := repository.RunGitCommand(ctx, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(strings.TrimSpace(string(top)), FileName)
+		if _, err := os.Stat(path); err == nil {
+			file, err := readGitConfig(ctx, repository, "--file", path)
+			if err != nil {
+				return nil, err
+			}
+			for i := range file {
+				file[i].Source = FileName
+			}
+			override(settings, file)
+		}
+	}
+
+	gitConfig, err := readGitConfig(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	override(settings, gitConfig)
+
+	var variables []Setting
+	for _, variable := range environment {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, environmentPrefix) || name == environmentPrefix {
+			continue
+		}
+		key := strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(name, environmentPrefix)), "_", "-")
+		// Repeatable options take several values separated by spaces
+		variables = append(variables, Setting{Key: key, Values: strings.Fields(value), Source: name})
+	}
+	override(settings, variables)
+
+	result := make([]Setting, 0, len(settings))
+	for _, setting := range settings {
+		result = append(result, setting)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// override replaces the settings of the same keys and their alternatives with the settings of a
+// later source. Alternatives within the same source are both kept.
+func override(settings map[string]Setting, source []Setting) {
+	for _, setting := range source {
+		delete(settings, alternativeKeys[setting.Key])
+	}
+	for _, setting := range source {
+		settings[setting.Key] = setting
+	}
+}
+
+// readGitConfig reads the gitmetrics.* keys of git config, or of the file given by the options,
+// and returns a setting per key with the values in the order git reads them
+func readGitConfig(ctx context.Context, repository git.Repository, options ...string) ([]Setting, error) {
+	args := append([]string{"config", "-z", "--show-origin"}, options...)
+	output, err := repository.RunGitCommand(ctx, append(args, "--get-regexp", `^`+section+`\.`)...)
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() == 1 {
+		return nil, nil // No key matches
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Each entry is the origin and the key followed by the value on its own line, all ended by NUL
+	var settings []Setting
+	indexes := make(map[string]int)
+	fields := bytes.Split(bytes.TrimSuffix(output, []byte{0}), []byte{0})
+	for i := 0; i+1 < len(fields); i += 2 {
+		origin := strings.TrimPrefix(string(fields[i]), "file:")
+		name, value, found := strings.Cut(string(fields[i+1]), "\n")
+		if !found {
+			value = "true" // A key without a value is a boolean that is set
+		}
+		key := strings.TrimPrefix(name, section+".")
+		index, exists := indexes[key]
+		if !exists {
+			index = len(settings)
+			indexes[key] = index
+			settings = append(settings, Setting{Key: key})
+		}
+		// Later values override scalar options, so the source of the last one is kept
+		settings[index].Values = append(settings[index].Values, value)
+		settings[index].Source = origin
+	}
+	return settings, nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"git-metrics/pkg/git"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	repositoryPath := t.TempDir()
+	if output, err := exec.Command("git", "init", "--quiet", repositoryPath).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	repository := git.Repository{Path: repositoryPath}
+
+	settings, err := Load(context.Background(), repository, nil)
+	if err != nil || len(settings) != 0 {
+		t.Fatalf("expected no settings, got %+v, %v", settings, err)
+	}
+
+	file := "[gitmetrics]\n\ttop-files = 20\n\tforecast-periods = 8\n\tbacktest\n\tconcern-threshold = disk-size=1GB:2GB\n\tconcern-threshold = commits=10:20\n"
+	if err := os.WriteFile(filepath.Join(repositoryPath, FileName), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command("git", "-C", repositoryPath, "config", "gitmetrics.top-files", "30").CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+	environment := []string{"HOME=/home/user", "GIT_METRICS_FORECAST_PERIODS=10", "GIT_METRICS_REFS=refs/heads/* refs/tags/*"}
+
+	settings, err = Load(context.Background(), repository, environment)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	expected := []Setting{
+		{Key: "backtest", Values: []string{"true"}, Source: FileName},
+		{Key: "concern-threshold", Values: []string{"disk-size=1GB:2GB", "commits=10:20"}, Source: FileName},
+		{Key: "forecast-periods", Values: []string{"10"}, Source: "GIT_METRICS_FORECAST_PERIODS"},
+		{Key: "refs", Values: []string{"refs/heads/*", "refs/tags/*"}, Source: "GIT_METRICS_REFS"},
+		{Key: "top-files", Values: []string{"30"}, Source: ".git/config"},
+	}
+	if !slices.EqualFunc(settings, expected, func(setting, expected Setting) bool {
+		return setting.Key == expected.Key && setting.Source == expected.Source && slices.Equal(setting.Values, expected.Values)
+	}) {
+		t.Errorf("Load() = %+v, expected %+v", settings, expected)
+	}
+
+	// A later source selecting the sections in another way overrides the earlier one, the same source keeps both
+	file = "[gitmetrics]\n\tsections = growth\n\tskip = rates\n"
+	if err := os.WriteFile(filepath.Join(repositoryPath, FileName), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		environment []string
+		expected    []string
+	}{
+		{environment: nil, expected: []string{"sections", "skip"}},
+		{environment: []string{"GIT_METRICS_SKIP=files"}, expected: []string{"skip"}},
+		{environment: []string{"GIT_METRICS_SECTIONS=files"}, expected: []string{"sections"}},
+	} {
+		settings, err := Load(context.Background(), repository, test.environment)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		var keys []string
+		for _, setting := range settings {
+			if Alternative(setting.Key) != "" {
+				keys = append(keys, setting.Key)
+			}
+		}
+		if !slices.Equal(keys, test.expected) {
+			t.Errorf("expected %v with %v, got %+v", test.expected, test.environment, settings)
+		}
+	}
+
+	// Paths that are no repository are left to the analysis to report
+	if settings, err := Load(context.Background(), git.Repository{Path: t.TempDir()}, environment); err != nil || settings != nil {
+		t.Errorf("expected no settings outside of a repository, got %+v, %v", settings, err)
+	}
+}
@@ -0,0 +1,80 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"git-metrics/pkg/models"
+	"io"
+)
+
+// ErrUnsupportedSchemaVersion is returned for a JSON report written with another schema version
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+// JSONRenderer writes the complete report as a single JSON document once all sections are collected
+type JSONRenderer struct {
+	Writer io.Writer
+}
+
+// StartSection does nothing as the JSON document is only written when finished
+func (renderer *JSONRenderer) StartSection(section models.Section) {}
+
+// StartPeriod does nothing as the JSON document is only written when finished
+func (renderer *JSONRenderer) StartPeriod(period models.Period, previous models.GrowthStatistics, beforePrevious models.GrowthStatistics) {
+}
+
+// FinishPeriod does nothing as the JSON document is only written when finished
+func (renderer *JSONRenderer) FinishPeriod(statistics models.GrowthStatistics, previous models.GrowthStatistics) {
+}
+
+// RenderSection does nothing as the JSON document is only written when finished
+func (renderer *JSONRenderer) RenderSection(section models.Section, report models.Report) {}
+
+// Finish writes the report to the renderer's writer
+func (renderer *JSONRenderer) Finish(report models.Report) error {
+	return WriteJSONReport(renderer.Writer, report)
+}
+
+// WriteJSONReport writes the report as an indented JSON document
+func WriteJSONReport(writer io.Writer, report models.Report) error {
+	// Keep empty lists as [] instead of null so consumers can rely on the schema
+	if report.Growth == nil {
+		report.Growth = []models.GrowthStatistics{}
+	}
+	if report.Estimates == nil {
+		report.Estimates = []models.GrowthStatistics{}
+	}
+	if report.ConcernProjections == nil {
+		report.ConcernProjections = []models.ConcernProjection{}
+	}
+	if report.FileExtensionGrowth == nil {
+		report.FileExtensionGrowth = []models.FileExtensionGrowthReport{}
+	}
+
+	return writeJSON(writer, report)
+}
+
+// ReadJSONReport reads a report written by WriteJSONReport with the current schema version
+func ReadJSONReport(reader io.Reader) (models.Report, error) {
+	var report models.Report
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return report, err
+	}
+	if report.SchemaVersion != models.ReportSchemaVersion {
+		return report, fmt.Errorf("%w %d", ErrUnsupportedSchemaVersion, report.SchemaVersion)
+	}
+	return report, nil
+}
+
+// WriteJSONReportDiff writes the comparison of two reports as an indented JSON document
+func WriteJSONReportDiff(writer io.Writer, diff models.ReportDiff) error {
+	return writeJSON(writer, diff)
+}
+
+// writeJSON writes the value as an indented JSON document
+func writeJSON(writer io.Writer, value any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,112 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"git-metrics/pkg/models"
+)
+
+func TestWriteJSONReport(t *testing.T) {
+	report := models.Report{
+		SchemaVersion: models.ReportSchemaVersion,
+		Repository:    &models.RepositoryInformation{TotalCommits: 12, CompressedSize: 7268},
+		Growth: []models.GrowthStatistics{
+			{Period: models.Period{Interval: models.QuarterInterval, Index: 2024*4 + 2}, Commits: 12, CommitsDelta: 12, LargestFiles: []models.FileInformation{{Path: "README.md"}}},
+		},
+		LargestFiles: models.LargestFilesReport{
+			Files: []models.FileInformation{{Path: "docs/<draft> & notes.md", Blobs: 1, CompressedSize: 34}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteJSONReport(&buffer, report); err != nil {
+		t.Fatalf("WriteJSONReport() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\nOutput: %s", err, buffer.String())
+	}
+
+	if decoded["schemaVersion"] != float64(models.ReportSchemaVersion) {
+		t.Errorf("expected schemaVersion %d, got %v", models.ReportSchemaVersion, decoded["schemaVersion"])
+	}
+
+	for _, expected := range []string{`"totalCommits": 12`, `"commitsDelta": 12`, `"period": "2024-Q3"`, `"path": "docs/<draft> & notes.md"`} {
+		if !strings.Contains(buffer.String(), expected) {
+			t.Errorf("expected output to contain %q.\nOutput: %s", expected, buffer.String())
+		}
+	}
+
+	// Per-year file lists are internal and would make the document grow with every year
+	if strings.Contains(buffer.String(), `"path": "README.md"`) {
+		t.Errorf("expected per-year largest files to be omitted.\nOutput: %s", buffer.String())
+	}
+
+	// Reports of a range do not describe the repository
+	buffer.Reset()
+	if err := WriteJSONReport(&buffer, models.Report{SchemaVersion: models.ReportSchemaVersion, Range: &models.RangeReport{Commits: 1}}); err != nil {
+		t.Fatalf("WriteJSONReport() error = %v", err)
+	}
+	if strings.Contains(buffer.String(), `"repository"`) || !strings.Contains(buffer.String(), `"range"`) {
+		t.Errorf("expected only the range without the repository.\nOutput: %s", buffer.String())
+	}
+}
+
+func TestJSONRendererWritesOnFinish(t *testing.T) {
+	var buffer bytes.Buffer
+	renderer := &JSONRenderer{Writer: &buffer}
+	report := models.Report{SchemaVersion: models.ReportSchemaVersion}
+
+	renderer.StartSection(models.GrowthSection)
+	renderer.RenderSection(models.GrowthSection, report)
+	if buffer.Len() != 0 {
+		t.Fatalf("expected no output before Finish, got: %s", buffer.String())
+	}
+
+	if err := renderer.Finish(report); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if !strings.Contains(buffer.String(), `"estimates": []`) {
+		t.Errorf("expected empty lists to be written as [].\nOutput: %s", buffer.String())
+	}
+}
+
+func TestNewRendererRejectsUnsupportedFormat(t *testing.T) {
+	if _, err := NewRenderer("xml"); err == nil {
+		t.Error("expected an error for an unsupported output format")
+	}
+	for _, format := range []string{TextFormat, JSONFormat} {
+		if _, err := NewRenderer(format); err != nil {
+			t.Errorf("NewRenderer(%q) error = %v", format, err)
+		}
+	}
+}
+
+func TestReadJSONReport(t *testing.T) {
+	report := models.Report{
+		SchemaVersion: models.ReportSchemaVersion,
+		Interval:      models.QuarterInterval,
+		Growth:        []models.GrowthStatistics{{Period: models.Period{Interval: models.QuarterInterval, Index: 2024*4 + 2}, Commits: 12}},
+		Authors:       models.ContributorReport{AllTime: []models.Contributor{{Name: "Alice", Commits: 12}}},
+	}
+	var buffer bytes.Buffer
+	if err := WriteJSONReport(&buffer, report); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadJSONReport(&buffer)
+	if err != nil {
+		t.Fatalf("ReadJSONReport() error = %v", err)
+	}
+	if read.Growth[0].Period != report.Growth[0].Period || read.Authors.AllTime[0] != report.Authors.AllTime[0] {
+		t.Errorf("expected the report as written, got %+v", read)
+	}
+
+	if _, err := ReadJSONReport(strings.NewReader(`{"schemaVersion": 1}`)); !errors.Is(err, ErrUnsupportedSchemaVersion) {
+		t.Errorf("expected ErrUnsupportedSchemaVersion, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package display
+
+import (
+	"fmt"
+	"git-metrics/pkg/display/sections"
+	"git-metrics/pkg/models"
+	"os"
+)
+
+// Supported output formats
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// Renderer presents report sections in a specific output format.
+// It receives the progress of an analysis (see metrics.Observer) and Finish is
+// called with the complete report after all sections are collected.
+type Renderer interface {
+	StartSection(section models.Section)
+	StartPeriod(period models.Period, previous models.GrowthStatistics, beforePrevious models.GrowthStatistics)
+	FinishPeriod(statistics models.GrowthStatistics, previous models.GrowthStatistics)
+	RenderSection(section models.Section, report models.Report)
+	Finish(report models.Report) error
+}
+
+// NewRenderer returns the renderer for the given output format writing to standard output
+func NewRenderer(format string) (Renderer, error) {
+	switch format {
+	case TextFormat:
+		return &TextRenderer{}, nil
+	case JSONFormat:
+		return &JSONRenderer{Writer: os.Stdout}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q. Use --format %s or --format %s", format, TextFormat, JSONFormat)
+	}
+}
+
+// PrintReportDiff writes the comparison of two reports in the given output format to standard output
+func PrintReportDiff(format string, diff models.ReportDiff) error {
+	switch format {
+	case TextFormat:
+		sections.PrintReportDiffSectionTitle()
+		sections.DisplayReportDiff(diff)
+		return nil
+	case JSONFormat:
+		return WriteJSONReportDiff(os.Stdout, diff)
+	default:
+		return fmt.Errorf("unsupported output format %q. Use --format %s or --format %s", format, TextFormat, JSONFormat)
+	}
+}
@@ -0,0 +1,169 @@
+package sections
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git-metrics/pkg/models"
+)
+
+// backtestHorizon is the number of periods ahead the forecasts are compared with the actual totals
+const backtestHorizon = 5
+
+// BuildBacktest replays every forecast model at the end of each complete period of the history and
+// compares its forecasts with the totals the repository actually reached in the following complete
+// periods. A forecast only uses the statistics up to the period it is made in. It returns nil when
+// the history is too short for a single forecast to be compared.
+func BuildBacktest(periodStatistics map[models.Period]models.GrowthStatistics, information models.RepositoryInformation, interval models.Interval) *models.BacktestReport {
+	currentPeriod := interval.PeriodOf(information.AsOf)
+
+	var periods []models.Period
+	for period := range periodStatistics {
+		// The last complete period has no complete period to compare its forecasts with
+		if period.Index < currentPeriod.Index-1 {
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
+
+	report := &models.BacktestReport{}
+	for _, model := range models.ForecastModels {
+		estimator := NewEstimator(model)
+		results := make([]models.BacktestResult, backtestHorizon)
+		for i := range results {
+			results[i] = models.BacktestResult{Model: model, Horizon: i + 1}
+		}
+
+		for _, period := range periods {
+			if !hasEstimationHistory(information, period) {
+				continue
+			}
+			// At the end of the period its totals are final, so only the following periods are forecast
+			estimates := CalculateNewEstimate(periodStatistics, period, period.End(), estimator, backtestHorizon)
+			if len(estimates) == 0 {
+				continue
+			}
+			for _, estimate := range estimates[1:] {
+				horizon := estimate.Period.Index - period.Index
+				reached, exists := periodStatistics[estimate.Period]
+				if !exists || estimate.Period.Index >= currentPeriod.Index || reached.Commits == 0 {
+					continue
+				}
+				result := &results[horizon-1]
+				result.Forecasts++
+				addDeviation(&result.CommitsError, &result.CommitsBias, float64(estimate.Commits), float64(reached.Commits))
+				addDeviation(&result.UncompressedError, &result.UncompressedBias, float64(estimate.Uncompressed), float64(reached.Uncompressed))
+				addDeviation(&result.CompressedError, &result.CompressedBias, float64(estimate.Compressed), float64(reached.Compressed))
+				if report.First.Interval == "" || period.Index < report.First.Index {
+					report.First = period
+				}
+				if period.Index > report.Last.Index {
+					report.Last = period
+				}
+			}
+		}
+
+		for _, result := range results {
+			if result.Forecasts == 0 {
+				continue
+			}
+			// Turn the sums of the deviations into means
+			forecasts := float64(result.Forecasts)
+			result.CommitsError /= forecasts
+			result.CommitsBias /= forecasts
+			result.UncompressedError /= forecasts
+			result.UncompressedBias /= forecasts
+			result.CompressedError /= forecasts
+			result.CompressedBias /= forecasts
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	if len(report.Results) == 0 {
+		return nil
+	}
+	return report
+}
+
+// addDeviation adds the deviation of a forecast from the actual value as percentage of the actual
+// value to the sums of the absolute and the signed deviations
+func addDeviation(absoluteSum, signedSum *float64, forecast, actual float64) {
+	if actual == 0 {
+		return
+	}
+	deviation := (forecast - actual) / actual * 100
+	*absoluteSum += math.Abs(deviation)
+	*signedSum += deviation
+}
+
+// PrintBacktestSectionTitle prints the section title banner for the forecast backtest
+func PrintBacktestSectionTitle() {
+	fmt.Println("\nFORECAST BACKTEST ######################################################################################################")
+}
+
+// DisplayBacktest prints the errors of the forecast models by the number of periods forecast ahead
+func DisplayBacktest(report *models.BacktestReport, interval models.Interval) {
+	if report == nil || len(report.Results) == 0 {
+		return
+	}
+	noun := interval.Noun()
+
+	const columns = "%-10s %11s %10s │%18s %8s │%18s %8s │%18s %8s\n"
+	fmt.Println()
+	fmt.Printf(columns, "Model", "Ahead", "Forecasts", "Commits error", "bias", "Object size error", "bias", "On-disk size error", "bias")
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+
+	formatError := func(value float64) string { return fmt.Sprintf("%.1f %%", value) }
+	formatBias := func(value float64) string { return fmt.Sprintf("%+.1f %%", value) }
+	var previousModel models.ForecastModel
+	for _, result := range report.Results {
+		model := string(result.Model)
+		if result.Model == previousModel {
+			model = ""
+		}
+		previousModel = result.Model
+		ahead := fmt.Sprintf("%d %s", result.Horizon, noun)
+		if result.Horizon > 1 {
+			ahead += "s"
+		}
+		fmt.Printf(columns,
+			model, ahead, strconv.Itoa(result.Forecasts),
+			formatError(result.CommitsError), formatBias(result.CommitsBias),
+			formatError(result.UncompressedError), formatBias(result.UncompressedBias),
+			formatError(result.CompressedError), formatBias(result.CompressedBias))
+	}
+
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	fmt.Println()
+	fmt.Printf("Forecasts made at the end of each %s from %s to %s with the history up to then\n", noun, report.First, report.Last)
+	fmt.Println("error: mean deviation from the actual totals, bias: mean signed deviation, negative if forecasts were too low")
+	fmt.Printf("Lowest error 1 %s ahead: %s\n", noun, strings.Join(mostAccurateModels(report.Results), ", "))
+}
+
+// mostAccurateModels names the model with the lowest error one period ahead for each metric
+func mostAccurateModels(results []models.BacktestResult) []string {
+	metrics := []struct {
+		name  string
+		error func(models.BacktestResult) float64
+	}{
+		{"commits", func(result models.BacktestResult) float64 { return result.CommitsError }},
+		{"object size", func(result models.BacktestResult) float64 { return result.UncompressedError }},
+		{"on-disk size", func(result models.BacktestResult) float64 { return result.CompressedError }},
+	}
+	var names []string
+	for _, metric := range metrics {
+		var best *models.BacktestResult
+		for i := range results {
+			if results[i].Horizon == 1 && (best == nil || metric.error(results[i]) < metric.error(*best)) {
+				best = &results[i]
+			}
+		}
+		if best != nil {
+			names = append(names, fmt.Sprintf("%s %s", metric.name, best.Model))
+		}
+	}
+	return names
+}
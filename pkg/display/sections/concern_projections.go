@@ -0,0 +1,175 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// concernProjectionYears limits how far beyond the current period thresholds are projected
+const concernProjectionYears = 20
+
+// concernMetricNames holds the display name of each metric in models.ConcernMetrics
+var concernMetricNames = map[string]string{
+	models.CommitsConcernMetric:     "Commits",
+	models.ObjectSizeConcernMetric:  "Object size",
+	models.DiskSizeConcernMetric:    "On-disk size",
+	models.LargestBlobConcernMetric: "Largest blob",
+	models.BlobsConcernMetric:       "Blobs",
+	models.TreesConcernMetric:       "Trees",
+}
+
+// estimatedConcernMetrics holds the value of the metrics the growth estimates project
+var estimatedConcernMetrics = map[string]func(models.GrowthStatistics) int64{
+	models.CommitsConcernMetric:    func(statistics models.GrowthStatistics) int64 { return int64(statistics.Commits) },
+	models.ObjectSizeConcernMetric: func(statistics models.GrowthStatistics) int64 { return statistics.Uncompressed },
+	models.DiskSizeConcernMetric:   func(statistics models.GrowthStatistics) int64 { return statistics.Compressed },
+}
+
+// BuildConcernProjections evaluates the current value of each metric against the thresholds and
+// projects when commits, object size and on-disk size reach the on-road to concerning and the
+// concerning level. Thresholds not crossed by the estimates are projected by continuing the delta of
+// the last estimate for up to 20 years. Without estimates, only the current levels are evaluated.
+func BuildConcernProjections(information models.RepositoryInformation, files []models.FileInformation, estimates []models.GrowthStatistics, thresholds models.ConcernThresholds) []models.ConcernProjection {
+	current := map[string]int64{
+		models.CommitsConcernMetric:    int64(information.TotalCommits),
+		models.ObjectSizeConcernMetric: information.UncompressedSize,
+		models.DiskSizeConcernMetric:   information.CompressedSize,
+		models.BlobsConcernMetric:      int64(information.TotalBlobs),
+		models.TreesConcernMetric:      int64(information.TotalTrees),
+	}
+	for _, file := range files {
+		current[models.LargestBlobConcernMetric] = max(current[models.LargestBlobConcernMetric], file.LargestBlob)
+	}
+
+	var projections []models.ConcernProjection
+	for _, metric := range models.ConcernMetrics {
+		threshold, exists := thresholds[metric]
+		if !exists {
+			continue
+		}
+		for _, level := range []struct {
+			name      string
+			threshold int64
+		}{{models.OnRoadConcernLevel, threshold.OnRoad}, {models.ConcerningConcernLevel, threshold.Concerning}} {
+			projection := models.ConcernProjection{Metric: metric, Level: level.name, Threshold: level.threshold, Value: current[metric]}
+			projection.Reached = projection.Value >= level.threshold
+			if value, estimated := estimatedConcernMetrics[metric]; estimated && !projection.Reached && len(estimates) > 0 {
+				projection.Period, projection.Extrapolated = projectThreshold(estimates, value, level.threshold)
+			}
+			projections = append(projections, projection)
+		}
+	}
+	return projections
+}
+
+// projectThreshold returns the period of the first estimate whose value reaches the threshold. Beyond
+// the estimates, the delta of the last estimate is continued for up to 20 years.
+func projectThreshold(estimates []models.GrowthStatistics, value func(models.GrowthStatistics) int64, threshold int64) (*models.Period, bool) {
+	for _, estimate := range estimates {
+		if value(estimate) >= threshold {
+			period := estimate.Period
+			return &period, false
+		}
+	}
+
+	last := estimates[len(estimates)-1]
+	limit := estimates[0].Period.Add(concernProjectionYears * last.Period.Interval.PeriodsPerYear())
+	if delta := value(last) - value(estimates[max(0, len(estimates)-2)]); delta > 0 {
+		// The periods are compared with the limit before they are added, which overflows for huge thresholds
+		distance := threshold - value(last)
+		periods := distance / delta
+		if distance%delta != 0 {
+			periods++
+		}
+		if periods <= int64(limit.Index-last.Period.Index) {
+			period := last.Period.Add(int(periods))
+			return &period, true
+		}
+	}
+	return nil, false
+}
+
+// formatConcernValue formats the value of a metric as size or count
+func formatConcernValue(metric string, value int64) string {
+	if models.IsSizeConcernMetric(metric) {
+		return strings.TrimSpace(utils.FormatSize(value))
+	}
+	return utils.FormatNumber(int(value))
+}
+
+// PrintConcernProjectionsSectionTitle prints the section title banner for the concern level projections
+func PrintConcernProjectionsSectionTitle() {
+	fmt.Println("\nCONCERN LEVEL PROJECTIONS ##############################################################################################")
+}
+
+// DisplayConcernProjections prints the level of concern of each metric and when it is projected to
+// reach the following levels, followed by a sentence for each metric projected to reach a higher level
+func DisplayConcernProjections(projections []models.ConcernProjection, thresholds models.ConcernThresholds, estimated bool, information models.RepositoryInformation, interval models.Interval) {
+	noun := interval.Noun()
+	limit := interval.PeriodOf(information.AsOf).Add(concernProjectionYears * interval.PeriodsPerYear())
+
+	byMetric := map[string][]models.ConcernProjection{}
+	for _, projection := range projections {
+		byMetric[projection.Metric] = append(byMetric[projection.Metric], projection)
+	}
+	describe := func(projection models.ConcernProjection) string {
+		_, projected := estimatedConcernMetrics[projection.Metric]
+		switch {
+		case projection.Reached:
+			return "reached"
+		case projection.Period != nil && projection.Extrapolated:
+			return "~" + projection.Period.String()
+		case projection.Period != nil:
+			return projection.Period.String()
+		case !projected || !estimated:
+			return "not estimated"
+		}
+		return "after " + limit.String()
+	}
+
+	const columns = "%-14s %14s %s │%24s %-13s │%24s %s\n"
+	fmt.Println()
+	fmt.Printf(columns, "Metric", "Current", " ", "◑ On-road to concerning", "reached", "● Concerning", "reached")
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	var sentences []string
+	for _, metric := range models.ConcernMetrics {
+		levels := byMetric[metric]
+		if len(levels) != 2 {
+			continue
+		}
+		fmt.Printf(columns,
+			concernMetricNames[metric], formatConcernValue(metric, levels[0].Value), thresholds.Level(metric, levels[0].Value),
+			formatConcernValue(metric, levels[0].Threshold), describe(levels[0]),
+			formatConcernValue(metric, levels[1].Threshold), describe(levels[1]))
+
+		var reaches []string
+		for _, level := range levels {
+			if level.Period != nil {
+				name := map[string]string{models.OnRoadConcernLevel: "on-road to concerning", models.ConcerningConcernLevel: "concerning"}[level.Level]
+				reaches = append(reaches, fmt.Sprintf("%s level in %s", name, describe(level)))
+			}
+		}
+		if len(reaches) > 0 {
+			sentences = append(sentences, fmt.Sprintf("%s reaches %s", concernMetricNames[metric], strings.Join(reaches, " and ")))
+		}
+	}
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	fmt.Println()
+	for _, sentence := range sentences {
+		fmt.Println(sentence)
+	}
+	if !estimated {
+		fmt.Printf("Concern level projection unavailable: Requires at least 2 %ss of commit history\n", noun)
+	} else if len(sentences) == 0 {
+		fmt.Printf("No metric is projected to reach a higher level of concern by %s\n", limit)
+	}
+	if estimated {
+		fmt.Println()
+		fmt.Printf("reached: %s the metric is projected to reach the level in with the estimated growth\n", noun)
+		fmt.Printf("~ Beyond the estimates, continuing the delta (Δ) of the last estimated %s\n", noun)
+		fmt.Println("not estimated: the growth estimates do not project the metric")
+	}
+}
@@ -0,0 +1,80 @@
+package sections
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"git-metrics/pkg/models"
+)
+
+func TestBuildConcernProjections(t *testing.T) {
+	const gigabyte = 1000 * 1000 * 1000
+	information := models.RepositoryInformation{TotalCommits: 1000000, TotalBlobs: 6000000, TotalTrees: 100, CompressedSize: 900 * 1000 * 1000, UncompressedSize: 12 * gigabyte}
+	files := []models.FileInformation{{Path: "video.mp4", LargestBlob: 60 * 1000 * 1000}, {Path: "README.md", LargestBlob: 1000}}
+	var estimates []models.GrowthStatistics
+	for i := range 6 {
+		estimates = append(estimates, models.GrowthStatistics{
+			Period:       year(2025 + i),
+			Commits:      1200000 + 300000*i,
+			Compressed:   int64(1100*1000*1000 + gigabyte*i),
+			Uncompressed: 12 * gigabyte,
+		})
+	}
+	describe := func(projections []models.ConcernProjection) string {
+		var described []string
+		for _, projection := range projections {
+			description := fmt.Sprintf("%s %s", projection.Metric, projection.Level)
+			switch {
+			case projection.Reached:
+				description += " reached"
+			case projection.Period != nil && projection.Extrapolated:
+				description += " ~" + projection.Period.String()
+			case projection.Period != nil:
+				description += " " + projection.Period.String()
+			}
+			described = append(described, description)
+		}
+		return strings.Join(described, ", ")
+	}
+
+	projections := BuildConcernProjections(information, files, estimates, models.ConcernProfiles[models.DefaultConcernProfile])
+	expected := "commits on-road 2026, commits concerning, object-size on-road reached, object-size concerning, disk-size on-road 2025, disk-size concerning ~2044, " +
+		"largest-blob on-road, largest-blob concerning, blobs on-road reached, blobs concerning, trees on-road, trees concerning"
+	if describe(projections) != expected {
+		t.Errorf("expected %s, got %s", expected, describe(projections))
+	}
+
+	output := captureOutput(func() {
+		DisplayConcernProjections(projections, models.ConcernProfiles[models.DefaultConcernProfile], true, information, models.YearInterval)
+	})
+	if !strings.Contains(output, "On-disk size reaches on-road to concerning level in 2025 and concerning level in ~2044") {
+		t.Errorf("expected the on-disk size projection as sentence, got %s", output)
+	}
+
+	// Stricter thresholds are reached earlier
+	hosted := BuildConcernProjections(information, files, estimates, models.ConcernProfiles["hosted"])
+	if described := describe(hosted); !strings.Contains(described, "disk-size concerning 2029") || !strings.Contains(described, "largest-blob on-road reached") {
+		t.Errorf("expected the hosted profile to be concerned earlier, got %s", described)
+	}
+
+	// Without estimates only the current levels are evaluated
+	for _, projection := range BuildConcernProjections(information, files, nil, models.ConcernProfiles[models.DefaultConcernProfile]) {
+		if projection.Period != nil {
+			t.Errorf("expected no projected period without estimates, got %+v", projection)
+		}
+	}
+}
+
+func TestProjectThresholdWithHugeThreshold(t *testing.T) {
+	estimates := []models.GrowthStatistics{{Period: year(2025), Compressed: 1000}, {Period: year(2026), Compressed: 1001}}
+	compressed := func(statistics models.GrowthStatistics) int64 { return statistics.Compressed }
+
+	if period, extrapolated := projectThreshold(estimates, compressed, math.MaxInt64); period != nil || extrapolated {
+		t.Errorf("expected no projection for a threshold beyond the limit, got %v", period)
+	}
+	if period, extrapolated := projectThreshold(estimates, compressed, 1003); period == nil || !extrapolated || period.String() != "2028" {
+		t.Errorf("expected the extrapolated projection 2028, got %v", period)
+	}
+}
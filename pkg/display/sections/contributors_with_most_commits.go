@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
 )
 
@@ -26,21 +27,15 @@ const (
 	formatCommittersTableHeader = "Year     Committer (#1)         Commits        Committer (#2)         Commits        Committer (#3)         Commits"
 	formatCommittersDivider     = "------------------------------------------------------------------------------------------------------------------------" // 120 '-'
 
-	// Row formats for 3-column layout
-	formatThreeColumnRow = "%-6s │ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%%\n"
-	formatTwoColumnRow   = "%-6s │ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%%\n"
-	formatOneColumnRow   = "%-6s │ %-22s%8s %3.0f%%\n"
+	// Row formats for 3-column layout, the period column fits months and quarters like 2024-Q3
+	formatThreeColumnRow = "%-7s│ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%%\n"
+	formatTwoColumnRow   = "%-7s│ %-22s%8s %3.0f%% │ %-22s%8s %3.0f%%\n"
+	formatOneColumnRow   = "%-7s│ %-22s%8s %3.0f%%\n"
 
 	// Maximum contributor name length
 	maxNameLength = 22
 )
 
-// contributorStats holds contributor name and commit count
-type contributorStats struct {
-	name    string
-	commits int
-}
-
 // truncateContributorName truncates a contributor name to maxNameLength and adds ellipsis if needed
 func truncateContributorName(name string) string {
 	if utf8.RuneCountInString(name) <= maxNameLength {
@@ -51,6 +46,53 @@ func truncateContributorName(name string) string {
 	return string(runes[:maxNameLength-3]) + "..."
 }
 
+// BuildContributors converts the top contributors per period into a report and selects the
+// top contributors of all time (up to limit).
+// contributorsByPeriod holds name, commit count and period for the top contributors of each period.
+func BuildContributors(contributorsByPeriod map[models.Period][][3]string, totalContributorsByPeriod map[models.Period]int, totalCommitsByPeriod map[models.Period]int, allTimeContributors map[string]int, limit int) models.ContributorReport {
+	report := models.ContributorReport{TotalContributors: len(allTimeContributors)}
+
+	// Get periods and sort them
+	var periods []models.Period
+	for period := range contributorsByPeriod {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
+
+	for _, period := range periods {
+		contributorPeriod := models.ContributorPeriod{
+			Period:            period,
+			TotalCommits:      totalCommitsByPeriod[period],
+			TotalContributors: totalContributorsByPeriod[period],
+		}
+		for _, contributorData := range contributorsByPeriod[period] {
+			commits, _ := strconv.Atoi(contributorData[1])
+			contributorPeriod.Top = append(contributorPeriod.Top, models.Contributor{Name: contributorData[0], Commits: commits})
+		}
+		report.Periods = append(report.Periods, contributorPeriod)
+		report.AllTimeCommits += contributorPeriod.TotalCommits
+	}
+
+	// Fill all-time contributors slice from the map
+	for name, commits := range allTimeContributors {
+		report.AllTime = append(report.AllTime, models.Contributor{Name: name, Commits: commits})
+	}
+
+	// Sort by number of commits (descending) and then by name (ascending, case-insensitive) as a secondary criteria
+	sort.Slice(report.AllTime, func(i, j int) bool {
+		if report.AllTime[i].Commits != report.AllTime[j].Commits {
+			return report.AllTime[i].Commits > report.AllTime[j].Commits
+		}
+		return strings.ToLower(report.AllTime[i].Name) < strings.ToLower(report.AllTime[j].Name)
+	})
+
+	if len(report.AllTime) > limit {
+		report.AllTime = report.AllTime[:limit]
+	}
+
+	return report
+}
+
 // PrintAuthorsSectionTitle prints the section title banner for authors with most commits.
 func PrintAuthorsSectionTitle() {
 	fmt.Println(formatAuthorsHeader)
@@ -61,247 +103,74 @@ func PrintCommittersSectionTitle() {
 	fmt.Println(formatCommittersHeader)
 }
 
-// DisplayContributorsWithMostCommits displays the top commit authors and committers by number of commits per year
-func DisplayContributorsWithMostCommits(authorsByYear map[int][][3]string, totalAuthorsByYear map[int]int, totalCommitsByYear map[int]int,
-	committersByYear map[int][][3]string, totalCommittersByYear map[int]int, allTimeAuthors map[string]int, allTimeCommitters map[string]int) {
-
+// DisplayContributorsWithMostCommits displays the top commit authors and committers by number of commits per period
+func DisplayContributorsWithMostCommits(authors models.ContributorReport, committers models.ContributorReport, interval models.Interval) {
 	// Display Authors Section
 	PrintAuthorsSectionTitle()
-	DisplayAuthorsSection(authorsByYear, totalAuthorsByYear, totalCommitsByYear, allTimeAuthors)
+	DisplayAuthorsSection(authors, interval)
 
 	// Display Committers Section
 	PrintCommittersSectionTitle()
-	DisplayCommittersSection(committersByYear, totalCommittersByYear, totalCommitsByYear, allTimeCommitters)
+	DisplayCommittersSection(committers, interval)
 }
 
-// DisplayAuthorsSection displays the authors with most commits per year.
+// DisplayAuthorsSection displays the authors with most commits per period.
 // The section title banner must be printed by the caller before calling this function.
-func DisplayAuthorsSection(authorsByYear map[int][][3]string, totalAuthorsByYear map[int]int, totalCommitsByYear map[int]int, allTimeAuthors map[string]int) {
+func DisplayAuthorsSection(report models.ContributorReport, interval models.Interval) {
 	fmt.Println()
-	fmt.Println(formatAuthorsTableHeader)
+	fmt.Println(periodColumnHeader(formatAuthorsTableHeader, interval))
 	fmt.Println(formatAuthorsDivider)
 
-	// Get years and sort them
-	var years []int
-	for year := range authorsByYear {
-		years = append(years, year)
-	}
-	sort.Ints(years)
-
-	// For all-time totals calculation
-	allTimeAuthorCommits := make(map[string]int)
-	var allTimeTotalCommits int
-
-	// Print for each year
-	for _, year := range years {
-		authors := authorsByYear[year]
-		totalCommits := totalCommitsByYear[year]
-		allTimeTotalCommits += totalCommits
-
-		// Add commits to all-time author totals
-		for _, authorData := range authors {
-			authorName := authorData[0]
-			authorCommits, _ := strconv.Atoi(authorData[1])
-			allTimeAuthorCommits[authorName] += authorCommits
-		}
-
-		// Print year row with up to 3 authors
-		displayYearRowAuthors(year, authors, totalCommits)
-	}
-
-	// Display all-time authors summary
-	if len(years) > 0 {
-		displayAllTimeAuthors(allTimeAuthorCommits, allTimeTotalCommits, allTimeAuthors)
-	}
+	displayContributorReport(report)
 }
 
-// DisplayCommittersSection displays the committers with most commits per year.
+// DisplayCommittersSection displays the committers with most commits per period.
 // The section title banner must be printed by the caller before calling this function.
-func DisplayCommittersSection(committersByYear map[int][][3]string, totalCommittersByYear map[int]int, totalCommitsByYear map[int]int, allTimeCommitters map[string]int) {
+func DisplayCommittersSection(report models.ContributorReport, interval models.Interval) {
 	fmt.Println()
-	fmt.Println(formatCommittersTableHeader)
+	fmt.Println(periodColumnHeader(formatCommittersTableHeader, interval))
 	fmt.Println(formatCommittersDivider)
 
-	// Get years and sort them
-	var years []int
-	for year := range committersByYear {
-		years = append(years, year)
-	}
-	sort.Ints(years)
-
-	// For all-time totals calculation
-	allTimeCommitterCommits := make(map[string]int)
-	var allTimeTotalCommits int
-
-	// Print for each year
-	for _, year := range years {
-		committers := committersByYear[year]
-		totalCommits := totalCommitsByYear[year]
-		allTimeTotalCommits += totalCommits
-
-		// Add commits to all-time committer totals
-		for _, committerData := range committers {
-			committerName := committerData[0]
-			committerCommits, _ := strconv.Atoi(committerData[1])
-			allTimeCommitterCommits[committerName] += committerCommits
-		}
+	displayContributorReport(report)
+}
 
-		// Print year row with up to 3 committers
-		displayYearRowCommitters(year, committers, totalCommits)
+// displayContributorReport prints a row for each period followed by the all-time summary row
+func displayContributorReport(report models.ContributorReport) {
+	for _, period := range report.Periods {
+		displayContributorRow(period.Period.String(), period.Top, period.TotalCommits)
 	}
 
-	// Display all-time committers summary
-	if len(years) > 0 {
-		displayAllTimeCommitters(allTimeCommitterCommits, allTimeTotalCommits, allTimeCommitters)
+	// Display all-time summary
+	if len(report.Periods) > 0 {
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		displayContributorRow("Total", report.AllTime, report.AllTimeCommits)
 	}
 }
 
 // displayContributorRow displays a row of contributors with their commit counts and percentages
-func displayContributorRow(yearStr string, contributors [][3]string, totalCommits int) {
-	// Print the row based on how many contributors we actually have
-	if len(contributors) >= 3 {
-		// All 3 columns filled
-		contributor1Commits, _ := strconv.Atoi(contributors[0][1])
-		contributor1Percentage := float64(contributor1Commits) / float64(totalCommits) * 100
-		contributor2Commits, _ := strconv.Atoi(contributors[1][1])
-		contributor2Percentage := float64(contributor2Commits) / float64(totalCommits) * 100
-		contributor3Commits, _ := strconv.Atoi(contributors[2][1])
-		contributor3Percentage := float64(contributor3Commits) / float64(totalCommits) * 100
-
-		fmt.Printf(formatThreeColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0][0]), utils.FormatNumber(contributor1Commits), contributor1Percentage,
-			truncateContributorName(contributors[1][0]), utils.FormatNumber(contributor2Commits), contributor2Percentage,
-			truncateContributorName(contributors[2][0]), utils.FormatNumber(contributor3Commits), contributor3Percentage)
-	} else if len(contributors) == 2 {
-		// Only 2 columns filled
-		contributor1Commits, _ := strconv.Atoi(contributors[0][1])
-		contributor1Percentage := float64(contributor1Commits) / float64(totalCommits) * 100
-		contributor2Commits, _ := strconv.Atoi(contributors[1][1])
-		contributor2Percentage := float64(contributor2Commits) / float64(totalCommits) * 100
-
-		fmt.Printf(formatTwoColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0][0]), utils.FormatNumber(contributor1Commits), contributor1Percentage,
-			truncateContributorName(contributors[1][0]), utils.FormatNumber(contributor2Commits), contributor2Percentage)
-	} else if len(contributors) == 1 {
-		// Only 1 column filled
-		contributor1Commits, _ := strconv.Atoi(contributors[0][1])
-		contributor1Percentage := float64(contributor1Commits) / float64(totalCommits) * 100
-
-		fmt.Printf(formatOneColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0][0]), utils.FormatNumber(contributor1Commits), contributor1Percentage)
+func displayContributorRow(periodLabel string, contributors []models.Contributor, totalCommits int) {
+	percentage := func(contributor models.Contributor) float64 {
+		return float64(contributor.Commits) / float64(totalCommits) * 100
 	}
-}
 
-// displayContributorRowAllTime displays a row of contributors with their commit counts and percentages for all-time stats
-func displayContributorRowAllTime(yearStr string, contributors []contributorStats, totalCommits int) {
 	// Print the row based on how many contributors we actually have
 	if len(contributors) >= 3 {
 		// All 3 columns filled
-		contributor1Percentage := float64(contributors[0].commits) / float64(totalCommits) * 100
-		contributor2Percentage := float64(contributors[1].commits) / float64(totalCommits) * 100
-		contributor3Percentage := float64(contributors[2].commits) / float64(totalCommits) * 100
-
 		fmt.Printf(formatThreeColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0].name), utils.FormatNumber(contributors[0].commits), contributor1Percentage,
-			truncateContributorName(contributors[1].name), utils.FormatNumber(contributors[1].commits), contributor2Percentage,
-			truncateContributorName(contributors[2].name), utils.FormatNumber(contributors[2].commits), contributor3Percentage)
+			periodLabel,
+			truncateContributorName(contributors[0].Name), utils.FormatNumber(contributors[0].Commits), percentage(contributors[0]),
+			truncateContributorName(contributors[1].Name), utils.FormatNumber(contributors[1].Commits), percentage(contributors[1]),
+			truncateContributorName(contributors[2].Name), utils.FormatNumber(contributors[2].Commits), percentage(contributors[2]))
 	} else if len(contributors) == 2 {
 		// Only 2 columns filled
-		contributor1Percentage := float64(contributors[0].commits) / float64(totalCommits) * 100
-		contributor2Percentage := float64(contributors[1].commits) / float64(totalCommits) * 100
-
 		fmt.Printf(formatTwoColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0].name), utils.FormatNumber(contributors[0].commits), contributor1Percentage,
-			truncateContributorName(contributors[1].name), utils.FormatNumber(contributors[1].commits), contributor2Percentage)
+			periodLabel,
+			truncateContributorName(contributors[0].Name), utils.FormatNumber(contributors[0].Commits), percentage(contributors[0]),
+			truncateContributorName(contributors[1].Name), utils.FormatNumber(contributors[1].Commits), percentage(contributors[1]))
 	} else if len(contributors) == 1 {
 		// Only 1 column filled
-		contributor1Percentage := float64(contributors[0].commits) / float64(totalCommits) * 100
-
 		fmt.Printf(formatOneColumnRow,
-			yearStr,
-			truncateContributorName(contributors[0].name), utils.FormatNumber(contributors[0].commits), contributor1Percentage)
-	}
-}
-
-func displayYearRowAuthors(year int, authors [][3]string, totalCommits int) {
-	yearStr := fmt.Sprintf("%d", year)
-	displayContributorRow(yearStr, authors, totalCommits)
-}
-
-func displayYearRowCommitters(year int, committers [][3]string, totalCommits int) {
-	yearStr := fmt.Sprintf("%d", year)
-	displayContributorRow(yearStr, committers, totalCommits)
-}
-
-func displayAllTimeAuthors(allTimeAuthorCommits map[string]int, allTimeTotalCommits int, allTimeAuthors map[string]int) {
-	// Create slice for all-time authors
-	var allTimeAuthorsList []contributorStats
-
-	// Fill authors slice from the map
-	for name, commits := range allTimeAuthors {
-		allTimeAuthorsList = append(allTimeAuthorsList, contributorStats{name: name, commits: commits})
-	}
-
-	// Sort by number of commits (descending) and then by name (ascending, case-insensitive) as a secondary criteria
-	sort.Slice(allTimeAuthorsList, func(i, j int) bool {
-		if allTimeAuthorsList[i].commits != allTimeAuthorsList[j].commits {
-			return allTimeAuthorsList[i].commits > allTimeAuthorsList[j].commits
-		}
-		return strings.ToLower(allTimeAuthorsList[i].name) < strings.ToLower(allTimeAuthorsList[j].name)
-	})
-
-	// Limit to top 3 contributors
-	maxDisplayCount := 3
-	if len(allTimeAuthorsList) > maxDisplayCount {
-		allTimeAuthorsList = allTimeAuthorsList[:maxDisplayCount]
+			periodLabel,
+			truncateContributorName(contributors[0].Name), utils.FormatNumber(contributors[0].Commits), percentage(contributors[0]))
 	}
-
-	// Print all-time stats
-	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-
-	// Print total row for all-time authors
-	displayYearRowAuthorsAllTime("Total", allTimeAuthorsList, allTimeTotalCommits)
-}
-
-func displayAllTimeCommitters(allTimeCommitterCommits map[string]int, allTimeTotalCommits int, allTimeCommitters map[string]int) {
-	// Create slice for all-time committers
-	var allTimeCommittersList []contributorStats
-
-	// Fill committers slice from the map
-	for name, commits := range allTimeCommitters {
-		allTimeCommittersList = append(allTimeCommittersList, contributorStats{name: name, commits: commits})
-	}
-
-	// Sort by number of commits (descending) and then by name (ascending, case-insensitive) as a secondary criteria
-	sort.Slice(allTimeCommittersList, func(i, j int) bool {
-		if allTimeCommittersList[i].commits != allTimeCommittersList[j].commits {
-			return allTimeCommittersList[i].commits > allTimeCommittersList[j].commits
-		}
-		return strings.ToLower(allTimeCommittersList[i].name) < strings.ToLower(allTimeCommittersList[j].name)
-	})
-
-	// Limit to top 3 contributors
-	maxDisplayCount := 3
-	if len(allTimeCommittersList) > maxDisplayCount {
-		allTimeCommittersList = allTimeCommittersList[:maxDisplayCount]
-	}
-
-	// Print all-time stats
-	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-
-	// Print total row for all-time committers
-	displayYearRowCommittersAllTime("Total", allTimeCommittersList, allTimeTotalCommits)
-}
-
-func displayYearRowAuthorsAllTime(yearStr string, authors []contributorStats, totalCommits int) {
-	displayContributorRowAllTime(yearStr, authors, totalCommits)
-}
-
-func displayYearRowCommittersAllTime(yearStr string, committers []contributorStats, totalCommits int) {
-	displayContributorRowAllTime(yearStr, committers, totalCommits)
 }
@@ -0,0 +1,274 @@
+package sections
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// diffMetricNames holds the display name of each metric of the totals of a models.ReportDiff
+var diffMetricNames = map[string]string{
+	models.CommitsMetric:    "Commits",
+	models.AuthorsMetric:    "Authors",
+	models.TreesMetric:      "Trees",
+	models.BlobsMetric:      "Blobs",
+	models.ObjectSizeMetric: "Object size",
+	models.DiskSizeMetric:   "On-disk size",
+}
+
+// objectSections are the sections whose collection walks the objects of the history, which the
+// repository totals other than the number of authors are counted by
+var objectSections = []models.Section{
+	models.GrowthSection, models.ConcernSection, models.BacktestSection, models.FileExtensionsSection,
+	models.FileExtensionGrowthSection, models.DirectoriesSection, models.FilesSection, models.PolicySection,
+}
+
+// BuildReportDiff compares the totals, largest files, largest directories and contributors of two
+// reports of a repository. Only the data both reports contain is compared, so reports of runs
+// limited to some sections can be compared as well.
+func BuildReportDiff(old models.Report, new models.Report) models.ReportDiff {
+	diff := models.ReportDiff{
+		SchemaVersion:    models.ReportSchemaVersion,
+		OldAsOf:          old.Repository.AsOf,
+		NewAsOf:          new.Repository.AsOf,
+		Totals:           []models.MetricChange{},
+		Sections:         []models.Section{},
+		NewLargestFiles:  []models.FileInformation{},
+		DirectoriesAbove: []models.DirectoryEntry{},
+		DirectoriesBelow: []models.DirectoryEntry{},
+		Authors:          []models.RankingChange{},
+		Committers:       []models.RankingChange{},
+	}
+	collected := func(sections ...models.Section) bool {
+		return slices.ContainsFunc(sections, old.Collected) && slices.ContainsFunc(sections, new.Collected)
+	}
+
+	if collected(objectSections...) {
+		diff.Totals = append(diff.Totals,
+			models.MetricChange{Metric: models.CommitsMetric, Old: int64(old.Repository.TotalCommits), New: int64(new.Repository.TotalCommits)},
+			models.MetricChange{Metric: models.AuthorsMetric, Old: int64(old.Repository.TotalAuthors), New: int64(new.Repository.TotalAuthors)},
+			models.MetricChange{Metric: models.TreesMetric, Old: int64(old.Repository.TotalTrees), New: int64(new.Repository.TotalTrees)},
+			models.MetricChange{Metric: models.BlobsMetric, Old: int64(old.Repository.TotalBlobs), New: int64(new.Repository.TotalBlobs)},
+			models.MetricChange{Metric: models.ObjectSizeMetric, Old: old.Repository.UncompressedSize, New: new.Repository.UncompressedSize},
+			models.MetricChange{Metric: models.DiskSizeMetric, Old: old.Repository.CompressedSize, New: new.Repository.CompressedSize},
+		)
+	}
+
+	if collected(models.FilesSection) {
+		diff.Sections = append(diff.Sections, models.FilesSection)
+		for _, file := range new.LargestFiles.Files {
+			if !slices.ContainsFunc(old.LargestFiles.Files, func(oldFile models.FileInformation) bool { return oldFile.Path == file.Path }) {
+				diff.NewLargestFiles = append(diff.NewLargestFiles, file)
+			}
+		}
+	}
+	if collected(models.DirectoriesSection) {
+		diff.Sections = append(diff.Sections, models.DirectoriesSection)
+		diff.OldDirectoryThreshold = old.LargestDirectories.Threshold
+		diff.NewDirectoryThreshold = new.LargestDirectories.Threshold
+		diff.DirectoriesAbove = directoriesMissingFrom(new.LargestDirectories.Entries, old.LargestDirectories.Entries)
+		diff.DirectoriesBelow = directoriesMissingFrom(old.LargestDirectories.Entries, new.LargestDirectories.Entries)
+	}
+	if collected(models.ContributorsSection) {
+		diff.Sections = append(diff.Sections, models.ContributorsSection)
+		diff.Authors = rankingChanges(old.Authors.AllTime, new.Authors.AllTime)
+		diff.Committers = rankingChanges(old.Committers.AllTime, new.Committers.AllTime)
+	}
+	return diff
+}
+
+// directoriesMissingFrom returns the directories of the entries that the other entries do not contain
+func directoriesMissingFrom(entries []models.DirectoryEntry, other []models.DirectoryEntry) []models.DirectoryEntry {
+	directories := []models.DirectoryEntry{}
+	for _, entry := range entries {
+		if !entry.IsFile && !slices.ContainsFunc(other, func(otherEntry models.DirectoryEntry) bool {
+			return !otherEntry.IsFile && otherEntry.Path == entry.Path
+		}) {
+			directories = append(directories, entry)
+		}
+	}
+	return directories
+}
+
+// rankingChanges returns the ranks of the contributors of the new ranking followed by the
+// contributors of the old ranking that are no longer ranked
+func rankingChanges(old []models.Contributor, new []models.Contributor) []models.RankingChange {
+	changes := []models.RankingChange{}
+	oldRank := func(name string) int {
+		return slices.IndexFunc(old, func(contributor models.Contributor) bool { return contributor.Name == name }) + 1
+	}
+	for i, contributor := range new {
+		change := models.RankingChange{Name: contributor.Name, NewRank: i + 1, NewCommits: contributor.Commits}
+		if rank := oldRank(contributor.Name); rank > 0 {
+			change.OldRank, change.OldCommits = rank, old[rank-1].Commits
+		}
+		changes = append(changes, change)
+	}
+	for i, contributor := range old {
+		if !slices.ContainsFunc(new, func(newContributor models.Contributor) bool { return newContributor.Name == contributor.Name }) {
+			changes = append(changes, models.RankingChange{Name: contributor.Name, OldRank: i + 1, OldCommits: contributor.Commits})
+		}
+	}
+	return changes
+}
+
+// PrintReportDiffSectionTitle prints the section title banner for the comparison of two reports
+func PrintReportDiffSectionTitle() {
+	fmt.Println("\nSNAPSHOT DIFF ##########################################################################################################")
+}
+
+// DisplayReportDiff prints what changed between two reports
+func DisplayReportDiff(diff models.ReportDiff) {
+	fmt.Println()
+	fmt.Printf("Old snapshot               %s\n", diff.OldAsOf.Local().Format("Mon, 02 Jan 2006 15:04 MST"))
+	fmt.Printf("New snapshot               %s\n", diff.NewAsOf.Local().Format("Mon, 02 Jan 2006 15:04 MST"))
+
+	fmt.Println()
+	if len(diff.Totals) == 0 {
+		printNotCompared("Repository totals")
+	} else {
+		fmt.Println("Metric                               Old              New                Δ          %")
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		for _, change := range diff.Totals {
+			delta, sign := change.New-change.Old, "+"
+			if delta < 0 {
+				delta, sign = -delta, "-"
+			}
+			fmt.Printf("%-26s %13s    %13s    %13s %10s\n", diffMetricNames[change.Metric],
+				formatDiffValue(change.Metric, change.Old), formatDiffValue(change.Metric, change.New),
+				sign+formatDiffValue(change.Metric, delta), formatChangePercent(change.Old, change.New))
+		}
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	}
+
+	fmt.Println()
+	if !slices.Contains(diff.Sections, models.FilesSection) {
+		printNotCompared("Largest files")
+	} else if len(diff.NewLargestFiles) == 0 {
+		fmt.Println("No new files among the largest files")
+	} else {
+		fmt.Println("New among the largest files")
+		fmt.Println()
+		fmt.Println("      Blobs    On-disk size   Path")
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		var footnotes []Footnote
+		for _, file := range diff.NewLargestFiles {
+			result := CreatePathFootnote(file.Path, PathColumnWidth, len(footnotes))
+			if result.Index > 0 {
+				footnotes = append(footnotes, Footnote{Index: result.Index, FullPath: result.FullPath})
+			}
+			fmt.Printf("%11s %15s   %s\n", utils.FormatNumber(file.Blobs), utils.FormatSize(file.CompressedSize), result.DisplayPath)
+		}
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		printFootnotes(footnotes)
+	}
+
+	fmt.Println()
+	threshold := fmt.Sprintf("%g%%", diff.NewDirectoryThreshold)
+	if diff.OldDirectoryThreshold != diff.NewDirectoryThreshold {
+		threshold = fmt.Sprintf("%g%% and %g%%", diff.OldDirectoryThreshold, diff.NewDirectoryThreshold)
+	}
+	if !slices.Contains(diff.Sections, models.DirectoriesSection) {
+		printNotCompared("Largest directories")
+	} else if len(diff.DirectoriesAbove)+len(diff.DirectoriesBelow) == 0 {
+		fmt.Printf("No directories crossed the threshold of %s of the on-disk size\n", threshold)
+	} else {
+		fmt.Printf("Directories that crossed the threshold of %s of the on-disk size\n", threshold)
+		fmt.Println()
+		fmt.Println("      Blobs    On-disk size   Change   Path")
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		var footnotes []Footnote
+		printDirectory := func(entry models.DirectoryEntry, change string) {
+			result := CreatePathFootnote(entry.Path+"/", PathColumnWidth-9, len(footnotes))
+			if result.Index > 0 {
+				footnotes = append(footnotes, Footnote{Index: result.Index, FullPath: result.FullPath})
+			}
+			fmt.Printf("%11s %15s   %-6s   %s\n", utils.FormatNumber(entry.Blobs), utils.FormatSize(entry.CompressedSize), change, result.DisplayPath)
+		}
+		for _, entry := range diff.DirectoriesAbove {
+			printDirectory(entry, "above")
+		}
+		for _, entry := range diff.DirectoriesBelow {
+			printDirectory(entry, "below")
+		}
+		fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		printFootnotes(footnotes)
+	}
+
+	if !slices.Contains(diff.Sections, models.ContributorsSection) {
+		fmt.Println()
+		printNotCompared("Contributors")
+		return
+	}
+	displayRankingChanges("Authors with most commits", diff.Authors)
+	displayRankingChanges("Committers with most commits", diff.Committers)
+}
+
+// printNotCompared notes that the data was left out of the comparison as a snapshot lacks it
+func printNotCompared(data string) {
+	fmt.Printf("%s not compared: not collected in both snapshots\n", data)
+}
+
+// displayRankingChanges prints the ranks of the contributors in the old and the new report
+func displayRankingChanges(title string, changes []models.RankingChange) {
+	fmt.Println()
+	if len(changes) == 0 {
+		fmt.Printf("%s: none in either snapshot\n", title)
+		return
+	}
+	fmt.Println(title)
+	fmt.Println()
+	fmt.Println("Rank   Contributor                   Commits   Old rank   Old commits   Change")
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	for _, change := range changes {
+		rank, oldRank, commits, oldCommits := "-", "-", "-", "-"
+		if change.NewRank > 0 {
+			rank, commits = fmt.Sprintf("#%d", change.NewRank), utils.FormatNumber(change.NewCommits)
+		}
+		if change.OldRank > 0 {
+			oldRank, oldCommits = fmt.Sprintf("#%d", change.OldRank), utils.FormatNumber(change.OldCommits)
+		}
+		movement := "unchanged"
+		switch {
+		case change.OldRank == 0:
+			movement = "new"
+		case change.NewRank == 0:
+			movement = "dropped out"
+		case change.NewRank < change.OldRank:
+			movement = fmt.Sprintf("up %d", change.OldRank-change.NewRank)
+		case change.NewRank > change.OldRank:
+			movement = fmt.Sprintf("down %d", change.NewRank-change.OldRank)
+		}
+		fmt.Printf("%-6s %-26s %10s %10s %13s   %s\n", rank, truncateContributorName(change.Name), commits, oldRank, oldCommits, movement)
+	}
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+}
+
+// formatDiffValue formats a value of a metric of the totals, sizes with their unit
+func formatDiffValue(metric string, value int64) string {
+	if metric == models.ObjectSizeMetric || metric == models.DiskSizeMetric {
+		return strings.TrimSpace(utils.FormatSize(value))
+	}
+	return utils.FormatNumber(int(value))
+}
+
+// formatChangePercent returns the change from the old to the new value as a percentage of the old value
+func formatChangePercent(old int64, new int64) string {
+	if old == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.1f %%", float64(new-old)/float64(old)*100)
+}
+
+// printFootnotes prints the full paths of the truncated paths of a table
+func printFootnotes(footnotes []Footnote) {
+	if len(footnotes) > 0 {
+		fmt.Println()
+		for _, footnote := range footnotes {
+			fmt.Printf("[%d] %s\n", footnote.Index, footnote.FullPath)
+		}
+	}
+}
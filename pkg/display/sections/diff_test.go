@@ -0,0 +1,84 @@
+package sections
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"git-metrics/pkg/models"
+)
+
+func TestBuildReportDiff(t *testing.T) {
+	// Each collected section is listed as a phase of the run
+	run := func(sections ...models.Section) models.RunInformation {
+		var phases []models.PhaseInformation
+		for _, section := range sections {
+			phases = append(phases, models.PhaseInformation{Section: section})
+		}
+		return models.RunInformation{Phases: phases}
+	}
+	complete := run(models.GrowthSection, models.DirectoriesSection, models.FilesSection, models.ContributorsSection)
+	old := models.Report{
+		Run:                complete,
+		Repository:         &models.RepositoryInformation{TotalCommits: 100, CompressedSize: 1000},
+		LargestFiles:       models.LargestFilesReport{Files: []models.FileInformation{{Path: "main.go"}}},
+		LargestDirectories: models.DirectoryReport{Threshold: 1, Entries: []models.DirectoryEntry{{Path: "."}, {Path: "docs"}, {Path: "main.go", IsFile: true}}},
+		Authors:            models.ContributorReport{AllTime: []models.Contributor{{Name: "Alice", Commits: 60}, {Name: "Bob", Commits: 40}}},
+	}
+	new := models.Report{
+		Run:                complete,
+		Repository:         &models.RepositoryInformation{TotalCommits: 150, CompressedSize: 5000},
+		LargestFiles:       models.LargestFilesReport{Files: []models.FileInformation{{Path: "assets/video.mp4"}, {Path: "main.go"}}},
+		LargestDirectories: models.DirectoryReport{Threshold: 1, Entries: []models.DirectoryEntry{{Path: "."}, {Path: "assets"}, {Path: "assets/video.mp4", IsFile: true}}},
+		Authors:            models.ContributorReport{AllTime: []models.Contributor{{Name: "Bob", Commits: 80}, {Name: "Carol", Commits: 65}}},
+	}
+
+	diff := BuildReportDiff(old, new)
+	if diff.Totals[0] != (models.MetricChange{Metric: models.CommitsMetric, Old: 100, New: 150}) {
+		t.Errorf("unexpected commits %+v", diff.Totals[0])
+	}
+	if len(diff.NewLargestFiles) != 1 || diff.NewLargestFiles[0].Path != "assets/video.mp4" {
+		t.Errorf("expected the video as new large file, got %+v", diff.NewLargestFiles)
+	}
+	if len(diff.DirectoriesAbove) != 1 || diff.DirectoriesAbove[0].Path != "assets" || len(diff.DirectoriesBelow) != 1 || diff.DirectoriesBelow[0].Path != "docs" {
+		t.Errorf("expected assets above and docs below the threshold, got %+v and %+v", diff.DirectoriesAbove, diff.DirectoriesBelow)
+	}
+	expected := []models.RankingChange{
+		{Name: "Bob", OldRank: 2, NewRank: 1, OldCommits: 40, NewCommits: 80},
+		{Name: "Carol", NewRank: 2, NewCommits: 65},
+		{Name: "Alice", OldRank: 1, OldCommits: 60},
+	}
+	if !slices.Equal(diff.Authors, expected) {
+		t.Errorf("rankingChanges() = %+v, expected %+v", diff.Authors, expected)
+	}
+
+	output := captureOutput(func() { DisplayReportDiff(diff) })
+	for _, text := range []string{"+400.0 %", "assets/video.mp4", "above    assets/", "up 1", "dropped out"} {
+		if !strings.Contains(output, text) {
+			t.Errorf("expected output to contain %q, got %s", text, output)
+		}
+	}
+
+	// A snapshot limited to the files leaves the directories and contributors out of the comparison
+	new.Run = run(models.FilesSection)
+	new.LargestDirectories, new.Authors = models.DirectoryReport{}, models.ContributorReport{}
+	diff = BuildReportDiff(old, new)
+	if !slices.Equal(diff.Sections, []models.Section{models.FilesSection}) || len(diff.NewLargestFiles) != 1 {
+		t.Errorf("expected only the files to be compared, got %+v", diff)
+	}
+	if len(diff.DirectoriesBelow) != 0 || len(diff.Authors) != 0 {
+		t.Errorf("expected no directories and authors, got %+v", diff)
+	}
+	if !slices.ContainsFunc(diff.Totals, func(change models.MetricChange) bool { return change.Metric == models.AuthorsMetric }) {
+		t.Errorf("expected the number of authors among the totals, got %+v", diff.Totals)
+	}
+	output = captureOutput(func() { DisplayReportDiff(diff) })
+	for _, text := range []string{"Largest directories not compared", "Contributors not compared"} {
+		if !strings.Contains(output, text) {
+			t.Errorf("expected output to contain %q, got %s", text, output)
+		}
+	}
+	if strings.Contains(output, "below") || strings.Contains(output, "dropped out") {
+		t.Errorf("expected no changes of the sections that were not compared, got %s", output)
+	}
+}
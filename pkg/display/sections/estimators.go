@@ -0,0 +1,165 @@
+package sections
+
+import (
+	"fmt"
+	"math"
+
+	"git-metrics/pkg/models"
+)
+
+// forecastHistory is the number of periods the compound and regression models learn from
+const forecastHistory = 5
+
+// Estimator projects the deltas (Δ) of a growth metric into the periods following the current one
+type Estimator interface {
+	// Project returns the deltas of the count periods following the given deltas, which are
+	// ordered from the oldest period to the current one and end with the current period's delta
+	Project(deltas []float64, count int) []float64
+	// Description explains the projection in the footnotes of the growth table, noun names a period
+	Description(noun string) string
+}
+
+// NewEstimator returns the estimator of the forecast model, the linear one if it is empty
+func NewEstimator(model models.ForecastModel) Estimator {
+	switch model {
+	case models.CompoundForecast:
+		return CompoundEstimator{}
+	case models.RegressionForecast:
+		return RegressionEstimator{}
+	}
+	return LinearEstimator{}
+}
+
+// LinearEstimator repeats the current period's delta, so the totals grow by the same amount every period
+type LinearEstimator struct{}
+
+// Project returns the current period's delta for every following period
+func (LinearEstimator) Project(deltas []float64, count int) []float64 {
+	projected := make([]float64, count)
+	for i := range projected {
+		projected[i] = deltas[len(deltas)-1]
+	}
+	return projected
+}
+
+// Description explains the linear projection
+func (LinearEstimator) Description(noun string) string {
+	return fmt.Sprintf("each following %s grows by the current %s's delta (Δ)", noun, noun)
+}
+
+// CompoundEstimator grows the current period's delta by the average delta percentage (Δ%) of the
+// recent periods, so accelerating repositories keep accelerating
+type CompoundEstimator struct{}
+
+// Project grows the current period's delta by the geometric mean of the recent changes between
+// consecutive deltas. Changes from or to periods without growth are left out.
+func (CompoundEstimator) Project(deltas []float64, count int) []float64 {
+	recent := deltas[max(0, len(deltas)-forecastHistory-1):]
+	var logarithms float64
+	var changes int
+	for i := 1; i < len(recent); i++ {
+		if recent[i-1] > 0 && recent[i] > 0 {
+			logarithms += math.Log(recent[i] / recent[i-1])
+			changes++
+		}
+	}
+	factor := 1.0
+	if changes > 0 {
+		factor = math.Exp(logarithms / float64(changes))
+	}
+
+	projected := make([]float64, count)
+	delta := deltas[len(deltas)-1]
+	for i := range projected {
+		delta *= factor
+		projected[i] = delta
+	}
+	return projected
+}
+
+// Description explains the compound projection
+func (CompoundEstimator) Description(noun string) string {
+	return fmt.Sprintf("deltas (Δ) grow by the average Δ%% of the last %d %ss", forecastHistory, noun)
+}
+
+// RegressionEstimator continues the least-squares trend line through the recent deltas, so steadily
+// increasing or decreasing growth is carried forward
+type RegressionEstimator struct{}
+
+// Project fits a line through the recent deltas and returns its values for the following periods.
+// Negative values are projected as no growth.
+func (RegressionEstimator) Project(deltas []float64, count int) []float64 {
+	recent := deltas[max(0, len(deltas)-forecastHistory-1):]
+	points := float64(len(recent))
+	var sumX, sumY, sumXY, sumXX float64
+	for x, y := range recent {
+		sumX += float64(x)
+		sumY += y
+		sumXY += float64(x) * y
+		sumXX += float64(x) * float64(x)
+	}
+	var slope float64
+	if denominator := points*sumXX - sumX*sumX; denominator != 0 {
+		slope = (points*sumXY - sumX*sumY) / denominator
+	}
+	intercept := (sumY - slope*sumX) / points
+
+	projected := make([]float64, count)
+	for i := range projected {
+		projected[i] = max(0, intercept+slope*float64(len(recent)+i))
+	}
+	return projected
+}
+
+// Description explains the regression projection
+func (RegressionEstimator) Description(noun string) string {
+	return fmt.Sprintf("deltas (Δ) follow the least-squares trend of the last %d %ss", forecastHistory+1, noun)
+}
+
+// confidenceFactor is the number of standard deviations on either side of an estimate that cover
+// 90% of normally distributed outcomes
+const confidenceFactor = 1.645
+
+// setConfidenceBands sets the range the totals of each estimate fall into with a probability of 90%,
+// assuming the deltas of the estimated periods vary as much as the historic deltas did. The first
+// estimate is the current period, of which the remaining share is still unknown. Totals never shrink,
+// so the low ends are at least the actual totals. Without two historic deltas, no range is set.
+func setConfidenceBands(estimates []models.GrowthStatistics, historic []models.GrowthStatistics, actual models.GrowthStatistics, remaining float64) {
+	if len(historic) < 2 {
+		return
+	}
+	deviation := func(delta func(models.GrowthStatistics) float64) float64 {
+		var sum float64
+		for _, statistics := range historic {
+			sum += delta(statistics)
+		}
+		mean := sum / float64(len(historic))
+		var squares float64
+		for _, statistics := range historic {
+			squares += (delta(statistics) - mean) * (delta(statistics) - mean)
+		}
+		return math.Sqrt(squares / float64(len(historic)-1))
+	}
+	authorsDeviation := deviation(func(statistics models.GrowthStatistics) float64 { return float64(statistics.AuthorsDelta) })
+	commitsDeviation := deviation(func(statistics models.GrowthStatistics) float64 { return float64(statistics.CommitsDelta) })
+	compressedDeviation := deviation(func(statistics models.GrowthStatistics) float64 { return float64(statistics.CompressedDelta) })
+	uncompressedDeviation := deviation(func(statistics models.GrowthStatistics) float64 { return float64(statistics.UncompressedDelta) })
+
+	for i := range estimates {
+		// The deltas of the unknown periods add up, so does their variance
+		spread := confidenceFactor * math.Sqrt(remaining+float64(i))
+		estimate := &estimates[i]
+		estimate.Low = &models.GrowthBound{
+			Authors:      max(actual.Authors, estimate.Authors-int(math.Round(spread*authorsDeviation))),
+			Commits:      max(actual.Commits, estimate.Commits-int(math.Round(spread*commitsDeviation))),
+			Compressed:   max(actual.Compressed, estimate.Compressed-int64(math.Round(spread*compressedDeviation))),
+			Uncompressed: max(actual.Uncompressed, estimate.Uncompressed-int64(math.Round(spread*uncompressedDeviation))),
+		}
+		estimate.High = &models.GrowthBound{
+			Authors:      estimate.Authors + int(math.Round(spread*authorsDeviation)),
+			Commits:      estimate.Commits + int(math.Round(spread*commitsDeviation)),
+			Compressed:   estimate.Compressed + int64(math.Round(spread*compressedDeviation)),
+			Uncompressed: estimate.Uncompressed + int64(math.Round(spread*uncompressedDeviation)),
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sections
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"git-metrics/pkg/models"
+)
+
+func TestEstimators(t *testing.T) {
+	tests := []struct {
+		model    models.ForecastModel
+		deltas   []float64
+		expected []float64
+	}{
+		{models.LinearForecast, []float64{100, 200, 400}, []float64{400, 400, 400}},
+		{models.CompoundForecast, []float64{100, 200, 400}, []float64{800, 1600, 3200}},
+		{models.CompoundForecast, []float64{100, 0, 400}, []float64{400, 400, 400}}, // No change between growing periods
+		{models.RegressionForecast, []float64{100, 200, 300}, []float64{400, 500, 600}},
+		{models.RegressionForecast, []float64{300, 200, 100}, []float64{0, 0, 0}}, // Shrinking growth ends at no growth
+		{models.RegressionForecast, []float64{250}, []float64{250, 250, 250}},
+	}
+	for _, test := range tests {
+		projected := NewEstimator(test.model).Project(test.deltas, len(test.expected))
+		for i := range test.expected {
+			if math.Abs(projected[i]-test.expected[i]) > 0.001 {
+				t.Errorf("%s projection of %v = %v, expected %v", test.model, test.deltas, projected, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestCalculateNewEstimateCompound(t *testing.T) {
+	// Accelerating growth of 100, 200 and 400 commits keeps accelerating instead of repeating the
+	// current year's delta, which is the previous year's delta early in the year
+	yearlyStats := map[models.Period]models.GrowthStatistics{
+		year(2021): {Period: year(2021), Commits: 100},
+		year(2022): {Period: year(2022), Commits: 200},
+		year(2023): {Period: year(2023), Commits: 400},
+		year(2024): {Period: year(2024), Commits: 800},
+		year(2025): {Period: year(2025), Commits: 800},
+	}
+
+	estimates := CalculateNewEstimate(yearlyStats, year(2025), time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), CompoundEstimator{}, 5)
+	if len(estimates) != 6 || estimates[0].CommitsDelta != 400 {
+		t.Fatalf("expected 6 estimates starting with 400 commits, got %+v", estimates)
+	}
+	for i := 1; i < len(estimates); i++ {
+		if estimates[i].CommitsDelta <= estimates[i-1].CommitsDelta {
+			t.Errorf("expected increasing commit deltas, got %d after %d in %v", estimates[i].CommitsDelta, estimates[i-1].CommitsDelta, estimates[i].Period)
+		}
+	}
+}
+
+func TestSetConfidenceBands(t *testing.T) {
+	// Deltas of 100, 200 and 300 commits deviate by 100 commits
+	historic := []models.GrowthStatistics{{CommitsDelta: 100}, {CommitsDelta: 200}, {CommitsDelta: 300}}
+	estimates := []models.GrowthStatistics{{Commits: 1000}, {Commits: 1300}}
+	setConfidenceBands(estimates, historic, models.GrowthStatistics{Commits: 950}, 0.25)
+
+	// Half a standard deviation remains for the current period, the actual totals are the lower limit
+	if estimates[0].Low.Commits != 950 || estimates[0].High.Commits != 1082 {
+		t.Errorf("expected the current period between 950 and 1082 commits, got %+v and %+v", estimates[0].Low, estimates[0].High)
+	}
+	// The next period adds its own variance
+	if estimates[1].Low.Commits != 1116 || estimates[1].High.Commits != 1484 {
+		t.Errorf("expected the next period between 1116 and 1484 commits, got %+v and %+v", estimates[1].Low, estimates[1].High)
+	}
+
+	withoutHistory := []models.GrowthStatistics{{Commits: 1000}}
+	setConfidenceBands(withoutHistory, historic[:1], models.GrowthStatistics{}, 1)
+	if withoutHistory[0].Low != nil || withoutHistory[0].High != nil {
+		t.Errorf("expected no range with a single historic delta, got %+v", withoutHistory[0])
+	}
+}
+
+func TestBuildBacktest(t *testing.T) {
+	// Commits grow by 100 every year while the object size doubles every year
+	yearlyStats := map[models.Period]models.GrowthStatistics{}
+	for i := range 11 {
+		yearlyStats[year(2015+i)] = models.GrowthStatistics{Period: year(2015 + i), Commits: 100 * (i + 1), Uncompressed: 1000 << i}
+	}
+	yearlyStats[year(2026)] = yearlyStats[year(2025)]
+	information := models.RepositoryInformation{
+		FirstDate: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		AsOf:      time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	report := BuildBacktest(yearlyStats, information, models.YearInterval)
+	if report == nil || report.First != year(2017) || report.Last != year(2024) {
+		t.Fatalf("expected forecasts made from 2017 to 2024, got %+v", report)
+	}
+	results := map[models.ForecastModel]map[int]models.BacktestResult{}
+	for _, result := range report.Results {
+		if results[result.Model] == nil {
+			results[result.Model] = map[int]models.BacktestResult{}
+		}
+		results[result.Model][result.Horizon] = result
+	}
+
+	// Forecasts of the incomplete current year are not compared
+	if results[models.LinearForecast][1].Forecasts != 8 || results[models.LinearForecast][5].Forecasts != 4 {
+		t.Errorf("expected 8 forecasts 1 year and 4 forecasts 5 years ahead, got %+v", results[models.LinearForecast])
+	}
+	if linear := results[models.LinearForecast][3]; linear.CommitsError > 0.001 || linear.UncompressedBias >= 0 {
+		t.Errorf("expected exact linear commit forecasts and too low object sizes, got %+v", linear)
+	}
+	if compound := results[models.CompoundForecast][3]; compound.UncompressedError > 0.001 {
+		t.Errorf("expected exact compound object size forecasts, got %+v", compound)
+	}
+
+	short := BuildBacktest(yearlyStats, models.RepositoryInformation{FirstDate: information.FirstDate, AsOf: time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)}, models.YearInterval)
+	if short != nil {
+		t.Errorf("expected no backtest for three complete years, got %+v", short)
+	}
+}
@@ -4,104 +4,133 @@ import (
 	"fmt"
 	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
-	"strconv"
+	"math"
 	"strings"
 	"time"
 )
 
-// CalculateNewEstimate calculates estimated growth using new prediction logic based on delta changes
-func CalculateNewEstimate(yearlyStats map[int]models.GrowthStatistics, currentYear int, fetchTimeStr string) []models.GrowthStatistics {
+// CalculateNewEstimate calculates estimated growth using new prediction logic based on delta changes.
+// The current period's delta is extrapolated from the time passed in the period until now, the
+// deltas of the given number of following periods are projected by the estimator. If now is after the
+// current period, its totals are used as they are.
+func CalculateNewEstimate(periodStatistics map[models.Period]models.GrowthStatistics, currentPeriod models.Period, now time.Time, estimator Estimator, periods int) []models.GrowthStatistics {
 	var estimates []models.GrowthStatistics
 
-	// Get current year statistics and previous year for comparison
-	currentStats, currentExists := yearlyStats[currentYear]
-	previousStats, previousExists := yearlyStats[currentYear-1]
+	// Get current period statistics and previous period for comparison
+	currentStats, currentExists := periodStatistics[currentPeriod]
+	previousStats, previousExists := periodStatistics[currentPeriod.Add(-1)]
 
 	if !currentExists || !previousExists {
 		return estimates
 	}
 
-	// Calculate current year deltas
+	// Calculate current period deltas
 	currentAuthorsDelta := currentStats.Authors - previousStats.Authors
 	currentCommitsDelta := currentStats.Commits - previousStats.Commits
 	currentCompressedSizeDelta := currentStats.Compressed - previousStats.Compressed
 	currentUncompressedSizeDelta := currentStats.Uncompressed - previousStats.Uncompressed
 
-	// Determine if we need to predict current year or use existing
-	var now time.Time
-	if fetchTimeStr != "" {
-		// Parse the fetch time string (format: "Mon, 02 Jan 2006 15:04 MST")
-		if parsedTime, err := time.Parse("Mon, 02 Jan 2006 15:04 MST", fetchTimeStr); err == nil {
-			now = parsedTime
-		} else {
-			now = time.Now() // fallback if parsing fails
+	// Determine if we need to predict current period or use existing
+	daysPassed := int(now.Sub(currentPeriod.Start()).Hours() / 24)
+	daysInPeriod := 365 / float64(currentPeriod.Interval.PeriodsPerYear())
+
+	var predictedCurrentPeriod models.GrowthStatistics
+	remaining := 1.0 // Share of the current period whose growth is estimated
+
+	// Get two periods ago for calculating historical growth rate
+	twoPeriodsAgoStats := periodStatistics[currentPeriod.Add(-2)]
+
+	// If less than 60 days into a year, or as far into a shorter period, use previous period's growth delta
+	if float64(daysPassed) < daysInPeriod*60/365 {
+		// Use the previous period's delta as the projected growth for the current period
+		predictedCurrentPeriod = models.GrowthStatistics{
+			Period:       currentPeriod,
+			Authors:      previousStats.Authors + (previousStats.Authors - twoPeriodsAgoStats.Authors),
+			Commits:      previousStats.Commits + (previousStats.Commits - twoPeriodsAgoStats.Commits),
+			Compressed:   previousStats.Compressed + (previousStats.Compressed - twoPeriodsAgoStats.Compressed),
+			Uncompressed: previousStats.Uncompressed + (previousStats.Uncompressed - twoPeriodsAgoStats.Uncompressed),
 		}
-	} else {
-		now = time.Now() // fallback if no fetch time available
-	}
-	currentYearTime := time.Date(currentYear, 1, 1, 0, 0, 0, 0, time.UTC)
-	daysPassed := int(now.Sub(currentYearTime).Hours() / 24)
-
-	var predictedCurrentYear models.GrowthStatistics
-
-	// Get two years ago for calculating historical growth rate
-	twoYearsAgoStats := yearlyStats[currentYear-2]
-
-	// If less than 2 months (60 days) into the year, use previous year's growth delta
-	if daysPassed < 60 {
-		// Use the previous year's delta as the projected growth for the current year
-		predictedCurrentYear = models.GrowthStatistics{
-			Year:         currentYear,
-			Authors:      previousStats.Authors + (previousStats.Authors - twoYearsAgoStats.Authors),
-			Commits:      previousStats.Commits + (previousStats.Commits - twoYearsAgoStats.Commits),
-			Compressed:   previousStats.Compressed + (previousStats.Compressed - twoYearsAgoStats.Compressed),
-			Uncompressed: previousStats.Uncompressed + (previousStats.Uncompressed - twoYearsAgoStats.Uncompressed),
+	} else if !now.Before(currentPeriod.End()) {
+		// Once the period is over, its totals are final
+		remaining = 0
+		predictedCurrentPeriod = models.GrowthStatistics{
+			Period:       currentPeriod,
+			Authors:      currentStats.Authors,
+			Commits:      currentStats.Commits,
+			Compressed:   currentStats.Compressed,
+			Uncompressed: currentStats.Uncompressed,
 		}
 	} else {
-		// If 2+ months into the year, predict full year by extrapolating current progress
+		// Further into the period, predict the full period by extrapolating current progress
+		remaining = max(0, 1-float64(daysPassed)/daysInPeriod)
 		commitsPerDay := float64(currentCommitsDelta) / float64(daysPassed)
 		authorsPerDay := float64(currentAuthorsDelta) / float64(daysPassed)
 		compressedSizePerDay := float64(currentCompressedSizeDelta) / float64(daysPassed)
 		uncompressedSizePerDay := float64(currentUncompressedSizeDelta) / float64(daysPassed)
 
-		predictedCurrentYear = models.GrowthStatistics{
-			Year:         currentYear,
-			Authors:      previousStats.Authors + int(authorsPerDay*365),
-			Commits:      previousStats.Commits + int(commitsPerDay*365),
-			Compressed:   previousStats.Compressed + int64(compressedSizePerDay*365),
-			Uncompressed: previousStats.Uncompressed + int64(uncompressedSizePerDay*365),
+		predictedCurrentPeriod = models.GrowthStatistics{
+			Period:       currentPeriod,
+			Authors:      previousStats.Authors + int(authorsPerDay*daysInPeriod),
+			Commits:      previousStats.Commits + int(commitsPerDay*daysInPeriod),
+			Compressed:   previousStats.Compressed + int64(compressedSizePerDay*daysInPeriod),
+			Uncompressed: previousStats.Uncompressed + int64(uncompressedSizePerDay*daysInPeriod),
 		}
 	}
 
-	estimates = append(estimates, predictedCurrentYear)
+	estimates = append(estimates, predictedCurrentPeriod)
 
-	// Calculate deltas for the predicted current year
-	currentYearAuthorsDelta := predictedCurrentYear.Authors - previousStats.Authors
-	currentYearCommitsDelta := predictedCurrentYear.Commits - previousStats.Commits
-	currentYearCompressedSizeDelta := predictedCurrentYear.Compressed - previousStats.Compressed
-	currentYearUncompressedSizeDelta := predictedCurrentYear.Uncompressed - previousStats.Uncompressed
+	// Calculate deltas for the predicted current period
+	currentPeriodAuthorsDelta := predictedCurrentPeriod.Authors - previousStats.Authors
+	currentPeriodCommitsDelta := predictedCurrentPeriod.Commits - previousStats.Commits
+	currentPeriodCompressedSizeDelta := predictedCurrentPeriod.Compressed - previousStats.Compressed
+	currentPeriodUncompressedSizeDelta := predictedCurrentPeriod.Uncompressed - previousStats.Uncompressed
 
-	// Store delta values for the predicted current year
-	predictedCurrentYear.AuthorsDelta = currentYearAuthorsDelta
-	predictedCurrentYear.CommitsDelta = currentYearCommitsDelta
-	predictedCurrentYear.CompressedDelta = currentYearCompressedSizeDelta
-	predictedCurrentYear.UncompressedDelta = currentYearUncompressedSizeDelta
+	// Store delta values for the predicted current period
+	predictedCurrentPeriod.AuthorsDelta = currentPeriodAuthorsDelta
+	predictedCurrentPeriod.CommitsDelta = currentPeriodCommitsDelta
+	predictedCurrentPeriod.CompressedDelta = currentPeriodCompressedSizeDelta
+	predictedCurrentPeriod.UncompressedDelta = currentPeriodUncompressedSizeDelta
 
 	// Update the first estimate in the slice with calculated deltas
-	estimates[0] = predictedCurrentYear
-
-	// Project future years using linear growth (same delta each year)
-	previousEstimate := predictedCurrentYear
-
-	for futureYear := currentYear + 1; futureYear <= currentYear+5; futureYear++ {
-		// Use the same deltas from the current year prediction for all future years (linear growth)
-		nextAuthorsDelta := currentYearAuthorsDelta
-		nextCommitsDelta := currentYearCommitsDelta
-		nextCompressedSizeDelta := currentYearCompressedSizeDelta
-		nextUncompressedSizeDelta := currentYearUncompressedSizeDelta
+	estimates[0] = predictedCurrentPeriod
+
+	// Project future periods from the deltas of the recent periods and the predicted current period
+	var recent []models.GrowthStatistics
+	for period := currentPeriod.Add(-forecastHistory); period.Index < currentPeriod.Index; period = period.Add(1) {
+		statistics, exists := periodStatistics[period]
+		before, beforeExists := periodStatistics[period.Add(-1)]
+		if exists && beforeExists {
+			recent = append(recent, models.GrowthStatistics{
+				AuthorsDelta:      statistics.Authors - before.Authors,
+				CommitsDelta:      statistics.Commits - before.Commits,
+				CompressedDelta:   statistics.Compressed - before.Compressed,
+				UncompressedDelta: statistics.Uncompressed - before.Uncompressed,
+			})
+		}
+	}
+	historic := recent
+	recent = append(recent, predictedCurrentPeriod)
+	project := func(delta func(models.GrowthStatistics) float64) []float64 {
+		deltas := make([]float64, len(recent))
+		for i, statistics := range recent {
+			deltas[i] = delta(statistics)
+		}
+		return estimator.Project(deltas, periods)
+	}
+	authorsDeltas := project(func(statistics models.GrowthStatistics) float64 { return float64(statistics.AuthorsDelta) })
+	commitsDeltas := project(func(statistics models.GrowthStatistics) float64 { return float64(statistics.CommitsDelta) })
+	compressedDeltas := project(func(statistics models.GrowthStatistics) float64 { return float64(statistics.CompressedDelta) })
+	uncompressedDeltas := project(func(statistics models.GrowthStatistics) float64 { return float64(statistics.UncompressedDelta) })
+
+	previousEstimate := predictedCurrentPeriod
+	for i := range periods {
+		nextAuthorsDelta := int(math.Round(authorsDeltas[i]))
+		nextCommitsDelta := int(math.Round(commitsDeltas[i]))
+		nextCompressedSizeDelta := int64(math.Round(compressedDeltas[i]))
+		nextUncompressedSizeDelta := int64(math.Round(uncompressedDeltas[i]))
 
 		nextEstimate := models.GrowthStatistics{
-			Year:         futureYear,
+			Period:       currentPeriod.Add(i + 1),
 			Authors:      previousEstimate.Authors + nextAuthorsDelta,
 			Commits:      previousEstimate.Commits + nextCommitsDelta,
 			Compressed:   previousEstimate.Compressed + nextCompressedSizeDelta,
@@ -111,11 +140,6 @@ func CalculateNewEstimate(yearlyStats map[int]models.GrowthStatistics, currentYe
 			CommitsDelta:      nextCommitsDelta,
 			CompressedDelta:   nextCompressedSizeDelta,
 			UncompressedDelta: nextUncompressedSizeDelta,
-			// Delta percentage values are not needed for linear growth
-			AuthorsDeltaPercent:      0,
-			CommitsDeltaPercent:      0,
-			CompressedDeltaPercent:   0,
-			UncompressedDeltaPercent: 0,
 		}
 		estimates = append(estimates, nextEstimate)
 
@@ -123,6 +147,7 @@ func CalculateNewEstimate(yearlyStats map[int]models.GrowthStatistics, currentYe
 		previousEstimate = nextEstimate
 	}
 
+	setConfidenceBands(estimates, historic, currentStats, remaining)
 	return estimates
 }
 
@@ -142,7 +167,7 @@ func PrintEstimatedGrowthTableHeader() {
 }
 
 // PrintGrowthEstimateRow prints a row in the estimated growth table
-func PrintGrowthEstimateRow(statistics, previous models.GrowthStatistics, information models.RepositoryInformation, currentYear int) {
+func PrintGrowthEstimateRow(statistics, previous models.GrowthStatistics, information models.RepositoryInformation, currentPeriod models.Period, thresholds models.ConcernThresholds) {
 	// Calculate delta values for this estimate row
 	currentCommitsDelta := statistics.Commits - previous.Commits
 	currentCompressedSizeDelta := statistics.Compressed - previous.Compressed
@@ -160,10 +185,10 @@ func PrintGrowthEstimateRow(statistics, previous models.GrowthStatistics, inform
 		uncompressedPercentage = float64(currentUncompressedSizeDelta) / float64(information.UncompressedSize) * 100
 	}
 
-	yearDisplay := strconv.Itoa(statistics.Year) + "*"
-	if statistics.Year == currentYear {
-		// Current year estimate uses ~ to distinguish from future year estimates (*)
-		yearDisplay = strconv.Itoa(statistics.Year) + "~"
+	periodDisplay := statistics.Period.String() + "*"
+	if statistics.Period == currentPeriod {
+		// Current period estimate uses ~ to distinguish from future period estimates (*)
+		periodDisplay = statistics.Period.String() + "~"
 	}
 
 	// Helper to format signed integers with thousand separators
@@ -206,78 +231,154 @@ func PrintGrowthEstimateRow(statistics, previous models.GrowthStatistics, inform
 	uncompressedPercentDisplay := formatPercent(uncompressedPercentage)
 
 	// Get Level of Concern (LoC) symbols
-	commitsLoC := utils.GetConcernLevel("commits", int64(statistics.Commits))
-	objectSizeLoC := utils.GetConcernLevel("object-size", statistics.Uncompressed)
-	diskSizeLoC := utils.GetConcernLevel("disk-size", statistics.Compressed)
+	commitsLoC := thresholds.Level(models.CommitsConcernMetric, int64(statistics.Commits))
+	objectSizeLoC := thresholds.Level(models.ObjectSizeConcernMetric, statistics.Uncompressed)
+	diskSizeLoC := thresholds.Level(models.DiskSizeConcernMetric, statistics.Compressed)
 
 	// Print with new formatting: Commits | Object size | On-disk size with LoC columns
-	fmt.Printf("%-6s %14s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s\n",
-		yearDisplay,
+	fmt.Printf("%-9s %11s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s\n",
+		periodDisplay,
 		utils.FormatNumber(statistics.Commits), commitsDeltaDisplay, commitsPercentDisplay, commitsLoC,
 		utils.FormatSize(statistics.Uncompressed), objectSizeDeltaDisplay, uncompressedPercentDisplay, objectSizeLoC,
 		utils.FormatSize(statistics.Compressed), sizeDeltaDisplay, compressedPercentDisplay, diskSizeLoC)
 }
 
+// PrintGrowthEstimateRangeRow prints the range the totals of an estimate are expected in below its row
+func PrintGrowthEstimateRangeRow(statistics models.GrowthStatistics) {
+	if statistics.Low == nil || statistics.High == nil {
+		return
+	}
+	formatRange := func(low, high string) string {
+		return strings.TrimSpace(low) + " – " + strings.TrimSpace(high)
+	}
+	fmt.Printf("%-9s %22s %9s │%27s %9s │%27s\n",
+		"  range",
+		formatRange(utils.FormatNumber(statistics.Low.Commits), utils.FormatNumber(statistics.High.Commits)), "",
+		formatRange(utils.FormatSize(statistics.Low.Uncompressed), utils.FormatSize(statistics.High.Uncompressed)), "",
+		formatRange(utils.FormatSize(statistics.Low.Compressed), utils.FormatSize(statistics.High.Compressed)))
+}
+
+// hasEstimationHistory reports whether the repository has enough history before the current period to estimate growth
+func hasEstimationHistory(information models.RepositoryInformation, currentPeriod models.Period) bool {
+	return currentPeriod.Index-1-currentPeriod.Interval.PeriodOf(information.FirstDate).Index > 0
+}
+
+// BuildGrowthEstimates calculates the estimated growth for the current and the given number of following periods
+// of the interval including the share of each period's delta of the current totals. The current
+// period is the period the repository is analyzed as of.
+// It returns nil when the repository has less than two periods of commit history.
+func BuildGrowthEstimates(periodStatistics map[models.Period]models.GrowthStatistics, information models.RepositoryInformation, interval models.Interval, estimator Estimator, periods int) []models.GrowthStatistics {
+	currentPeriod := interval.PeriodOf(information.AsOf)
+	if !hasEstimationHistory(information, currentPeriod) {
+		return nil
+	}
+
+	// The current totals only contain the commits fetched until the most recent fetch
+	now := information.AsOf
+	if fetchTime, err := time.Parse("Mon, 02 Jan 2006 15:04 MST", information.MostRecentFetch); err == nil && fetchTime.Before(now) {
+		now = fetchTime
+	}
+
+	estimates := CalculateNewEstimate(periodStatistics, currentPeriod, now, estimator, periods)
+	for i := range estimates {
+		if information.TotalAuthors > 0 {
+			estimates[i].AuthorsPercent = float64(estimates[i].AuthorsDelta) / float64(information.TotalAuthors) * 100
+		}
+		if information.TotalCommits > 0 {
+			estimates[i].CommitsPercent = float64(estimates[i].CommitsDelta) / float64(information.TotalCommits) * 100
+		}
+		if information.CompressedSize > 0 {
+			estimates[i].CompressedPercent = float64(estimates[i].CompressedDelta) / float64(information.CompressedSize) * 100
+		}
+		if information.UncompressedSize > 0 {
+			estimates[i].UncompressedPercent = float64(estimates[i].UncompressedDelta) / float64(information.UncompressedSize) * 100
+		}
+	}
+
+	return estimates
+}
+
+// PrintGrowthSectionTitle prints the historic and estimated growth section banner and table header.
+// It is printed before data collection so progress rows appear within the table.
+func PrintGrowthSectionTitle(interval models.Interval) {
+	fmt.Println()
+	fmt.Println("HISTORIC & ESTIMATED GROWTH ############################################################################################")
+	fmt.Println()
+
+	// Period widened to 9 for ^* marker
+	fmt.Println(periodColumnHeader("Year          Commits          Δ     %   ○     Object size            Δ     %   ○    On-disk size            Δ     %   ○", interval))
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+}
+
 // DisplayUnifiedGrowth handles the complete unified historic and estimated growth section
-func DisplayUnifiedGrowth(yearlyStatistics map[int]models.GrowthStatistics, repositoryInformation models.RepositoryInformation, firstCommitTime time.Time, recentFetch string, lastModified string) {
-	currentYear := time.Now().Year()
+// except for the section title, which is printed before data collection
+func DisplayUnifiedGrowth(growth []models.GrowthStatistics, estimates []models.GrowthStatistics, repositoryInformation models.RepositoryInformation, interval models.Interval, forecastModel models.ForecastModel, thresholds models.ConcernThresholds) {
+	currentPeriod := interval.PeriodOf(repositoryInformation.AsOf)
+	noun := interval.Noun()
 
-	// Table headers and footnotes are now printed before data collection in main.go
 	// Display historic growth data
 	var previousDelta models.GrowthStatistics
-	for year := repositoryInformation.FirstDate.Year(); year <= currentYear; year++ {
-		if cumulative, ok := yearlyStatistics[year]; ok {
-			// Add row separator before current year
-			if year == currentYear {
-				fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-			}
-			PrintGrowthHistoryRow(cumulative, cumulative, previousDelta, repositoryInformation, currentYear)
-			// Add row separator after current year
-			if year == currentYear {
-				fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-			}
-			previousDelta = cumulative
+	var previousPeriod models.GrowthStatistics
+	for _, cumulative := range growth {
+		period := cumulative.Period
+		// Add row separator before current period
+		if period == currentPeriod {
+			fmt.Println("------------------------------------------------------------------------------------------------------------------------")
 		}
+		PrintGrowthHistoryRow(cumulative, cumulative, previousDelta, repositoryInformation, currentPeriod, thresholds)
+		// Add row separator after current period
+		if period == currentPeriod {
+			fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+		}
+		if period == currentPeriod.Add(-1) {
+			previousPeriod = cumulative
+		}
+		previousDelta = cumulative
 	}
 
 	// Display estimated growth data if sufficient history exists
-	var estimationEndYear = time.Now().Year() - 1
-	var estimationYears = estimationEndYear - firstCommitTime.Year()
-
-	if estimationYears > 5 {
-		estimationYears = 5
-	}
-
-	if estimationYears > 0 {
-		// Use new prediction logic based on delta changes
-		estimates := CalculateNewEstimate(yearlyStatistics, currentYear, recentFetch)
-		for i, estimate := range estimates {
-			var previous models.GrowthStatistics
-			if i == 0 {
-				// For first estimate (current year), use previous year as comparison
-				previous = yearlyStatistics[currentYear-1]
-			} else {
-				// For subsequent estimates, use previous estimate
-				previous = estimates[i-1]
-			}
-			PrintGrowthEstimateRow(estimate, previous, repositoryInformation, currentYear)
+	for i, estimate := range estimates {
+		var previous models.GrowthStatistics
+		if i == 0 {
+			// For first estimate (current period), use previous period as comparison
+			previous = previousPeriod
+		} else {
+			// For subsequent estimates, use previous estimate
+			previous = estimates[i-1]
 		}
+		PrintGrowthEstimateRow(estimate, previous, repositoryInformation, currentPeriod, thresholds)
+		PrintGrowthEstimateRangeRow(estimate)
 	}
 
 	// Separator and footnotes
 	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
 	fmt.Println()
-	fmt.Println("% columns: each year's delta as share of current totals (^)")
+	fmt.Printf("%% columns: each %s's delta as share of current totals (^)\n", noun)
 	fmt.Println("○ columns: ○ = Unconcerning, ◑ = On-road to concerning, ● = Concerning")
-	if recentFetch != "" {
-		fmt.Printf("^ Current totals as of the most recent fetch on %s\n", recentFetch[:16])
+	if repositoryInformation.MostRecentFetch != "" {
+		fmt.Printf("^ Current totals as of the most recent fetch on %s\n", repositoryInformation.MostRecentFetch[:16])
+	} else if repositoryInformation.LastModified != "" {
+		fmt.Printf("^ Current totals as of Git directory's last modified: %s\n", repositoryInformation.LastModified[:16])
 	} else {
-		fmt.Printf("^ Current totals as of Git directory's last modified: %s\n", lastModified[:16])
+		fmt.Printf("^ Current totals as of %s\n", repositoryInformation.AsOf.Format("Mon, 02 Jan 2006 15:04 MST")[:16])
 	}
-	if estimationYears > 0 {
-		fmt.Println("~ Estimated growth for current year based on year to date deltas (Δ) extrapolated to full year")
-		fmt.Println("* Estimated growth based on current year's estimated delta percentages (Δ%)")
+	if hasEstimationHistory(repositoryInformation, currentPeriod) {
+		fmt.Printf("~ Estimated growth for current %s based on %s to date deltas (Δ) extrapolated to full %s\n", noun, noun, noun)
+		if forecastModel == "" {
+			forecastModel = models.LinearForecast
+		}
+		fmt.Printf("* Estimated growth with the %s forecast model: %s\n", forecastModel, NewEstimator(forecastModel).Description(noun))
+		var otherModels []string
+		for _, model := range models.ForecastModels {
+			if model != forecastModel {
+				otherModels = append(otherModels, string(model))
+			}
+		}
+		fmt.Printf("  Other forecast models: %s (select with --forecast-model)\n", strings.Join(otherModels, ", "))
+		if len(estimates) > 0 && estimates[0].Low != nil {
+			fmt.Printf("  range: totals expected with 90%% probability given the variance of the deltas of the last %d %ss\n", forecastHistory, noun)
+		}
 	} else {
-		fmt.Println("Growth estimation unavailable: Requires at least 2 years of commit history")
+		fmt.Printf("Growth estimation unavailable: Requires at least 2 %ss of commit history\n", noun)
 	}
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
-	"strconv"
 	"strings"
 )
 
@@ -20,15 +19,15 @@ func PrintGrowthHistoryHeader() {
 
 // PrintGrowthHistoryRow prints a combined cumulative + delta row.
 // statistics contains both cumulative totals and pre-calculated delta values.
-func PrintGrowthHistoryRow(statistics, _, previousStats models.GrowthStatistics, information models.RepositoryInformation, currentYear int) {
+func PrintGrowthHistoryRow(statistics, _, previousStats models.GrowthStatistics, information models.RepositoryInformation, currentPeriod models.Period, thresholds models.ConcernThresholds) {
 	// Use pre-calculated values from the statistics struct
 	commitsPercentage := statistics.CommitsPercent
 	compressedPercentage := statistics.CompressedPercent
 	uncompressedPercentage := statistics.UncompressedPercent
 
-	yearDisplay := strconv.Itoa(statistics.Year)
-	if statistics.Year == currentYear {
-		yearDisplay += "^"
+	periodDisplay := statistics.Period.String()
+	if statistics.Period == currentPeriod {
+		periodDisplay += "^"
 	}
 
 	// Helper to format signed integers with thousand separators
@@ -69,13 +68,13 @@ func PrintGrowthHistoryRow(statistics, _, previousStats models.GrowthStatistics,
 	uncompressedPercentDisplay := formatPercent(uncompressedPercentage)
 
 	// Get Level of Concern (LoC) symbols
-	commitsLoC := utils.GetConcernLevel("commits", int64(statistics.Commits))
-	objectSizeLoC := utils.GetConcernLevel("object-size", statistics.Uncompressed)
-	diskSizeLoC := utils.GetConcernLevel("disk-size", statistics.Compressed)
+	commitsLoC := thresholds.Level(models.CommitsConcernMetric, int64(statistics.Commits))
+	objectSizeLoC := thresholds.Level(models.ObjectSizeConcernMetric, statistics.Uncompressed)
+	diskSizeLoC := thresholds.Level(models.DiskSizeConcernMetric, statistics.Compressed)
 
 	// Print with new formatting: Commits | Object size | On-disk size with LoC columns
-	fmt.Printf("%-6s %14s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s\n",
-		yearDisplay,
+	fmt.Printf("%-9s %11s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s\n",
+		periodDisplay,
 		utils.FormatNumber(statistics.Commits), commitsDeltaDisplay, commitsPercentDisplay, commitsLoC,
 		utils.FormatSize(statistics.Uncompressed), objectSizeDeltaDisplay, uncompressedPercentDisplay, objectSizeLoC,
 		utils.FormatSize(statistics.Compressed), sizeDeltaDisplay, compressedPercentDisplay, diskSizeLoC)
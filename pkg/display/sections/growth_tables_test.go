@@ -28,6 +28,11 @@ func captureOutput(f func()) string {
 	return buf.String()
 }
 
+// year returns the period of a year
+func year(year int) models.Period {
+	return models.Period{Interval: models.YearInterval, Index: year}
+}
+
 func TestPrintGrowthHistoryHeader(t *testing.T) {
 	output := captureOutput(func() {
 		PrintGrowthHistoryHeader()
@@ -43,14 +48,14 @@ func TestPrintGrowthHistoryHeader(t *testing.T) {
 func TestPrintGrowthHistoryRow(t *testing.T) {
 	// First year statistics with delta values populated
 	cumulativePrev := models.GrowthStatistics{
-		Year: 2022, Commits: 800, Compressed: 4 * 1000 * 1000, Uncompressed: 8 * 1000 * 1000,
+		Period: year(2022), Commits: 800, Compressed: 4 * 1000 * 1000, Uncompressed: 8 * 1000 * 1000,
 		CommitsDelta: 800, CompressedDelta: 4 * 1000 * 1000, UncompressedDelta: 8 * 1000 * 1000,
 		CommitsPercent: 80, CompressedPercent: 80, UncompressedPercent: 80,
 	}
 
 	// Second year statistics with delta values
 	cumulative := models.GrowthStatistics{
-		Year: 2023, Commits: 1000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000,
+		Period: year(2023), Commits: 1000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000,
 		CommitsDelta: 200, CompressedDelta: 1 * 1000 * 1000, UncompressedDelta: 2 * 1000 * 1000,
 		CommitsPercent: 20, CompressedPercent: 20, UncompressedPercent: 20,
 	}
@@ -59,9 +64,9 @@ func TestPrintGrowthHistoryRow(t *testing.T) {
 
 	output := captureOutput(func() {
 		// First year row (no previous delta)
-		PrintGrowthHistoryRow(cumulativePrev, cumulativePrev, models.GrowthStatistics{}, info, 2023)
+		PrintGrowthHistoryRow(cumulativePrev, cumulativePrev, models.GrowthStatistics{}, info, year(2023), models.ConcernProfiles[models.DefaultConcernProfile])
 		// Second year row (with previous delta)
-		PrintGrowthHistoryRow(cumulative, cumulative, cumulativePrev, info, 2023)
+		PrintGrowthHistoryRow(cumulative, cumulative, cumulativePrev, info, year(2023), models.ConcernProfiles[models.DefaultConcernProfile])
 	})
 
 	// Check for cumulative totals, deltas and LoC symbols
@@ -75,13 +80,13 @@ func TestPrintGrowthHistoryRow(t *testing.T) {
 
 func TestPrintGrowthEstimateRow(t *testing.T) {
 	stats := models.GrowthStatistics{
-		Year: 2024, Commits: 1100, Trees: 2200, Blobs: 3300, Compressed: 6 * 1000 * 1000, Uncompressed: 12 * 1000 * 1000,
+		Period: year(2024), Commits: 1100, Trees: 2200, Blobs: 3300, Compressed: 6 * 1000 * 1000, Uncompressed: 12 * 1000 * 1000,
 	}
-	prev := models.GrowthStatistics{Year: 2023, Commits: 1000, Trees: 2000, Blobs: 3000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000}
+	prev := models.GrowthStatistics{Period: year(2023), Commits: 1000, Trees: 2000, Blobs: 3000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000}
 	info := models.RepositoryInformation{TotalCommits: 1000, TotalTrees: 2000, TotalBlobs: 3000, CompressedSize: 5 * 1000 * 1000, UncompressedSize: 10 * 1000 * 1000}
 
 	output := captureOutput(func() {
-		PrintGrowthEstimateRow(stats, prev, info, 2023)
+		PrintGrowthEstimateRow(stats, prev, info, year(2023), models.ConcernProfiles[models.DefaultConcernProfile])
 	})
 
 	if !strings.Contains(output, "2024*") {
@@ -91,13 +96,13 @@ func TestPrintGrowthEstimateRow(t *testing.T) {
 
 func TestPrintGrowthEstimateRowCurrentYear(t *testing.T) {
 	stats := models.GrowthStatistics{
-		Year: 2024, Commits: 1100, Trees: 2200, Blobs: 3300, Compressed: 6 * 1000 * 1000, Uncompressed: 12 * 1000 * 1000,
+		Period: year(2024), Commits: 1100, Trees: 2200, Blobs: 3300, Compressed: 6 * 1000 * 1000, Uncompressed: 12 * 1000 * 1000,
 	}
-	prev := models.GrowthStatistics{Year: 2023, Commits: 1000, Trees: 2000, Blobs: 3000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000}
+	prev := models.GrowthStatistics{Period: year(2023), Commits: 1000, Trees: 2000, Blobs: 3000, Compressed: 5 * 1000 * 1000, Uncompressed: 10 * 1000 * 1000}
 	info := models.RepositoryInformation{TotalCommits: 1000, TotalTrees: 2000, TotalBlobs: 3000, CompressedSize: 5 * 1000 * 1000, UncompressedSize: 10 * 1000 * 1000}
 
 	output := captureOutput(func() {
-		PrintGrowthEstimateRow(stats, prev, info, 2024) // currentYear is 2024, same as stats.Year
+		PrintGrowthEstimateRow(stats, prev, info, year(2024), models.ConcernProfiles[models.DefaultConcernProfile]) // The current period is 2024, same as stats.Period
 	})
 
 	if !strings.Contains(output, "2024~") {
@@ -115,7 +120,12 @@ func TestPrintLargestFiles(t *testing.T) {
 	totalFiles := 2
 
 	output := captureOutput(func() {
-		PrintLargestFiles(files, totalFilesCompressedSize, totalBlobs, totalFiles)
+		PrintLargestFiles(models.LargestFilesReport{
+			Files:               files,
+			TotalBlobs:          totalBlobs,
+			TotalCompressedSize: totalFilesCompressedSize,
+			TotalFiles:          totalFiles,
+		})
 	})
 
 	for _, expected := range []string{"LARGEST FILES", "file1.txt", "file2.txt", "1.0 MB", "500.0 KB"} {
@@ -133,10 +143,10 @@ func TestPrintTopFileExtensions(t *testing.T) {
 		{Path: "README", Blobs: 1, CompressedSize: 128 * 1000, UncompressedSize: 150 * 1000},
 	}
 	totalBlobs := 19
-	totalSize := int64(1000*1000 + 500*1000 + 256*1000 + 128*1000)
 
 	output := captureOutput(func() {
-		PrintTopFileExtensions(files, totalBlobs, totalSize)
+		PrintFileExtensionsSectionTitle()
+		PrintTopFileExtensions(BuildFileExtensions(files, 10), totalBlobs)
 	})
 
 	for _, expected := range []string{"LARGEST FILE EXTENSIONS", ".txt", ".go", "No Extension", "Object size", "On-disk size", "Compression ratio"} {
@@ -167,17 +177,17 @@ func TestHistoricalAndEstimateSeparation(t *testing.T) {
 
 func TestPrintFileExtensionGrowth(t *testing.T) {
 	// Create test data with multiple years
-	yearlyStats := map[int]models.GrowthStatistics{
-		2022: {
-			Year: 2022,
+	yearlyStats := map[models.Period]models.GrowthStatistics{
+		year(2022): {
+			Period: year(2022),
 			LargestFiles: []models.FileInformation{
 				{Path: "app.go", CompressedSize: 100 * 1000, UncompressedSize: 120 * 1000},
 				{Path: "README.md", CompressedSize: 50 * 1000, UncompressedSize: 60 * 1000},
 				{Path: "config.json", CompressedSize: 25 * 1000, UncompressedSize: 30 * 1000},
 			},
 		},
-		2023: {
-			Year: 2023,
+		year(2023): {
+			Period: year(2023),
 			LargestFiles: []models.FileInformation{
 				{Path: "app.go", CompressedSize: 200 * 1000, UncompressedSize: 240 * 1000},    // +100KB .go growth
 				{Path: "main.go", CompressedSize: 150 * 1000, UncompressedSize: 180 * 1000},   // +150KB .go growth (new file)
@@ -189,7 +199,8 @@ func TestPrintFileExtensionGrowth(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		PrintFileExtensionGrowth(yearlyStats)
+		PrintFileExtensionGrowthSectionTitle()
+		PrintFileExtensionGrowth(BuildFileExtensionGrowth(yearlyStats, 3), models.YearInterval)
 	})
 
 	// Check that the function produces expected content
@@ -211,15 +222,15 @@ func TestPrintFileExtensionGrowth(t *testing.T) {
 func TestCalculateNewEstimateEarlyInYear(t *testing.T) {
 	// When less than 60 days into the year, the estimation should use the previous year's
 	// growth delta instead of copying previous year's values (which would result in zero growth)
-	yearlyStats := map[int]models.GrowthStatistics{
-		2023: {Year: 2023, Commits: 500, Authors: 10, Compressed: 1000000, Uncompressed: 2000000},
-		2024: {Year: 2024, Commits: 800, Authors: 15, Compressed: 1500000, Uncompressed: 3000000},
-		2025: {Year: 2025, Commits: 810, Authors: 15, Compressed: 1510000, Uncompressed: 3010000}, // small delta since early in year
+	yearlyStats := map[models.Period]models.GrowthStatistics{
+		year(2023): {Period: year(2023), Commits: 500, Authors: 10, Compressed: 1000000, Uncompressed: 2000000},
+		year(2024): {Period: year(2024), Commits: 800, Authors: 15, Compressed: 1500000, Uncompressed: 3000000},
+		year(2025): {Period: year(2025), Commits: 810, Authors: 15, Compressed: 1510000, Uncompressed: 3010000}, // small delta since early in year
 	}
 
 	// Fetch time is early in 2025 (< 60 days)
-	fetchTime := "Mon, 15 Jan 2025 10:00 UTC"
-	estimates := CalculateNewEstimate(yearlyStats, 2025, fetchTime)
+	fetchTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	estimates := CalculateNewEstimate(yearlyStats, year(2025), fetchTime, LinearEstimator{}, 5)
 
 	if len(estimates) == 0 {
 		t.Fatal("expected estimates, got none")
@@ -240,22 +251,22 @@ func TestCalculateNewEstimateEarlyInYear(t *testing.T) {
 	for i := 1; i < len(estimates); i++ {
 		delta := estimates[i].Commits - estimates[i-1].Commits
 		if delta == 0 {
-			t.Errorf("year %d: expected non-zero commit delta, got 0", estimates[i].Year)
+			t.Errorf("%v: expected non-zero commit delta, got 0", estimates[i].Period)
 		}
 	}
 }
 
 func TestCalculateNewEstimateLaterInYear(t *testing.T) {
 	// When 60+ days into the year, extrapolation should produce non-zero growth
-	yearlyStats := map[int]models.GrowthStatistics{
-		2023: {Year: 2023, Commits: 500, Authors: 10, Compressed: 1000000, Uncompressed: 2000000},
-		2024: {Year: 2024, Commits: 800, Authors: 15, Compressed: 1500000, Uncompressed: 3000000},
-		2025: {Year: 2025, Commits: 900, Authors: 16, Compressed: 1600000, Uncompressed: 3200000},
+	yearlyStats := map[models.Period]models.GrowthStatistics{
+		year(2023): {Period: year(2023), Commits: 500, Authors: 10, Compressed: 1000000, Uncompressed: 2000000},
+		year(2024): {Period: year(2024), Commits: 800, Authors: 15, Compressed: 1500000, Uncompressed: 3000000},
+		year(2025): {Period: year(2025), Commits: 900, Authors: 16, Compressed: 1600000, Uncompressed: 3200000},
 	}
 
 	// Fetch time is mid-year 2025 (> 60 days)
-	fetchTime := "Wed, 01 Oct 2025 10:00 UTC"
-	estimates := CalculateNewEstimate(yearlyStats, 2025, fetchTime)
+	fetchTime := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+	estimates := CalculateNewEstimate(yearlyStats, year(2025), fetchTime, LinearEstimator{}, 5)
 
 	if len(estimates) == 0 {
 		t.Fatal("expected estimates, got none")
@@ -271,16 +282,16 @@ func TestCalculateNewEstimateLaterInYear(t *testing.T) {
 	for i := 1; i < len(estimates); i++ {
 		delta := estimates[i].Commits - estimates[i-1].Commits
 		if delta == 0 {
-			t.Errorf("year %d: expected non-zero commit delta, got 0", estimates[i].Year)
+			t.Errorf("%v: expected non-zero commit delta, got 0", estimates[i].Period)
 		}
 	}
 }
 
 func TestPrintFileExtensionGrowthInsufficientData(t *testing.T) {
 	// Test with only one year of data
-	yearlyStats := map[int]models.GrowthStatistics{
-		2023: {
-			Year: 2023,
+	yearlyStats := map[models.Period]models.GrowthStatistics{
+		year(2023): {
+			Period: year(2023),
 			LargestFiles: []models.FileInformation{
 				{Path: "app.go", CompressedSize: 200 * 1000, UncompressedSize: 240 * 1000},
 			},
@@ -288,7 +299,7 @@ func TestPrintFileExtensionGrowthInsufficientData(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		PrintFileExtensionGrowth(yearlyStats)
+		PrintFileExtensionGrowth(BuildFileExtensionGrowth(yearlyStats, 3), models.YearInterval)
 	})
 
 	// Should produce no output when there's insufficient data
@@ -296,3 +307,52 @@ func TestPrintFileExtensionGrowthInsufficientData(t *testing.T) {
 		t.Errorf("expected no output with insufficient data, got: %s", output)
 	}
 }
+
+func TestBuildFileExtensions(t *testing.T) {
+	files := []models.FileInformation{
+		{Path: "file1.txt", Blobs: 10, CompressedSize: 1000, UncompressedSize: 1200},
+		{Path: "file2.txt", Blobs: 5, CompressedSize: 500, UncompressedSize: 600},
+		{Path: "script.go", Blobs: 3, CompressedSize: 256, UncompressedSize: 320},
+		{Path: "README", Blobs: 1, CompressedSize: 128, UncompressedSize: 150},
+	}
+
+	report := BuildFileExtensions(files, 2)
+
+	if report.ExtensionCount != 3 {
+		t.Errorf("expected 3 extensions, got %d", report.ExtensionCount)
+	}
+	if len(report.Extensions) != 2 {
+		t.Fatalf("expected extensions to be limited to 2, got %d", len(report.Extensions))
+	}
+	if report.Extensions[0].Extension != ".txt" || report.Extensions[0].Files != 2 || report.Extensions[0].Blobs != 15 {
+		t.Errorf("unexpected first extension: %+v", report.Extensions[0])
+	}
+	if report.Total.CompressedSize != 1884 || report.Total.Files != 4 {
+		t.Errorf("unexpected totals: %+v", report.Total)
+	}
+}
+
+func TestBuildContributors(t *testing.T) {
+	topByYear := map[models.Period][][3]string{
+		year(2023): {{"Alice", "5", "2023"}, {"Bob", "3", "2023"}},
+		year(2024): {{"Bob", "4", "2024"}},
+	}
+	totalContributorsByYear := map[models.Period]int{year(2023): 2, year(2024): 1}
+	totalCommitsByYear := map[models.Period]int{year(2023): 8, year(2024): 4}
+	allTime := map[string]int{"Alice": 5, "Bob": 7, "carol": 1, "Dave": 1}
+
+	report := BuildContributors(topByYear, totalContributorsByYear, totalCommitsByYear, allTime, 3)
+
+	if len(report.Periods) != 2 || report.Periods[0].Period != year(2023) || report.Periods[0].Top[1].Commits != 3 {
+		t.Errorf("unexpected periods: %+v", report.Periods)
+	}
+	if report.AllTimeCommits != 12 {
+		t.Errorf("expected 12 all-time commits, got %d", report.AllTimeCommits)
+	}
+	expectedAllTime := []string{"Bob", "Alice", "carol"}
+	for i, name := range expectedAllTime {
+		if report.AllTime[i].Name != name {
+			t.Errorf("expected all-time contributor #%d to be %s, got %s", i+1, name, report.AllTime[i].Name)
+		}
+	}
+}
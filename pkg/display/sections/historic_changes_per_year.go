@@ -20,7 +20,7 @@ func PrintHistoricChangesPerYearHeader() {
 // PrintHistoricChangesPerYearRow prints a row of the historic changes per year table.
 // statistics here represent the delta (changes) for the year, not cumulative totals.
 // previousDelta represents the prior year's delta to compute relative change percentages.
-func PrintHistoricChangesPerYearRow(statistics, previousDelta models.GrowthStatistics, currentYear int) {
+func PrintHistoricChangesPerYearRow(statistics, previousDelta models.GrowthStatistics, currentYear models.Period) {
 	// Relative change from previous year's delta. Guard division by zero.
 	commitsDifference := 0.0
 	treesDifference := 0.0
@@ -40,12 +40,12 @@ func PrintHistoricChangesPerYearRow(statistics, previousDelta models.GrowthStati
 		compressedDifference = float64(statistics.Compressed-previousDelta.Compressed) / float64(previousDelta.Compressed) * 100
 	}
 
-	yearDisplay := fmt.Sprintf("%d", statistics.Year)
-	if statistics.Year == currentYear {
+	yearDisplay := statistics.Period.String()
+	if statistics.Period == currentYear {
 		yearDisplay += "^"
 	}
 
-	if previousDelta.Year == 0 { // First year: show raw deltas only without +0% noise
+	if previousDelta.Period == (models.Period{}) { // First year: show raw deltas only without +0% noise
 		// Need to preserve column alignment where each percentage block normally takes: ' %+5.0f %%  ' => 8 chars
 		blank := "        " // 8 spaces placeholder
 		fmt.Printf("%-5s %13s %s %13s %s %13s %s %13s %s\n",
@@ -58,7 +58,7 @@ func PrintHistoricChangesPerYearRow(statistics, previousDelta models.GrowthStati
 	}
 
 	// For the last (current) year, print a separator before the row (if there is at least one previous delta year)
-	if statistics.Year == currentYear && previousDelta.Year != 0 {
+	if statistics.Period == currentYear && previousDelta.Period != (models.Period{}) {
 		fmt.Println("------------------------------------------------------------------------------------------------")
 	}
 
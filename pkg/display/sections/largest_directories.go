@@ -2,9 +2,7 @@ package sections
 
 import (
 	"fmt"
-	"git-metrics/pkg/git"
 	"git-metrics/pkg/models"
-	"git-metrics/pkg/progress"
 	"git-metrics/pkg/utils"
 	"path/filepath"
 	"sort"
@@ -13,23 +11,24 @@ import (
 )
 
 const (
-	// MaxDirectoryDepth is the maximum depth of directories to process
-	MaxDirectoryDepth = 10
+	// DefaultMaxDirectoryDepth is the maximum depth of directories to process unless configured otherwise
+	DefaultMaxDirectoryDepth = 10
 
-	// CompressedSizePercentageThreshold is the minimum percentage of total compressed size
-	// required for a directory or file to be considered significant (1%)
-	CompressedSizePercentageThreshold = 0.01
+	// DefaultDirectoryThreshold is the minimum percentage of total compressed size required for a
+	// directory or file to be considered significant unless configured otherwise
+	DefaultDirectoryThreshold = 1.0
 
-	// PathColumnWidth is the fixed width for the path column in the output table
-	PathColumnWidth = 76
-
-	// MaxTreeLevels is the maximum number of tree levels to process (MaxDirectoryDepth + 1 for root)
-	MaxTreeLevels = MaxDirectoryDepth + 1
+	// DefaultPathColumnWidth is the width of the path column unless configured otherwise
+	DefaultPathColumnWidth = 76
 
 	// TableRowFormat is the format string for printing table rows
 	TableRowFormat = "%11s%6.1f %%   %11s%6.1f %%   %s\n"
 )
 
+// PathColumnWidth is the width of the path column of the largest directories and files tables.
+// Longer paths are truncated and printed in full as footnotes.
+var PathColumnWidth = DefaultPathColumnWidth
+
 // Footnote contains the formatted display path and footnote information
 type Footnote struct {
 	DisplayPath string // The path string to display, possibly truncated with a footnote marker
@@ -90,21 +89,12 @@ func CreatePathFootnote(path string, maxDisplayLength int, currentFootnoteCount
 	return result
 }
 
-// PrintLargestDirectories prints directories and files that are >= 1% of total on-disk size, up to 10 levels deep
-func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, totalCompressedSize int64) {
-	fmt.Println("\nLARGEST DIRECTORIES ####################################################################################################")
-	progress.StartSectionSpinner()
-
-	type entry struct {
-		Path                  string
-		FullPath              string // Full path from root
-		Blobs                 int
-		CompressedSize        int64
-		Level                 int
-		IsFile                bool
-		ExistsInDefaultBranch bool
-		treePrefix            string // Tree formatting prefix
-	}
+// BuildLargestDirectories collects directories and files that are at least thresholdPercent of the
+// total on-disk size, up to maxDepth levels deep, in tree order starting with the repository root.
+// Entries are compared against the files of the default branch to flag moved, renamed or
+// removed paths; a non-nil defaultBranchError disables that comparison.
+func BuildLargestDirectories(files []models.FileInformation, totalBlobs int, defaultBranch string, defaultBranchFiles map[string]bool, defaultBranchError error, maxDepth int, thresholdPercent float64) models.DirectoryReport {
+	type entry = models.DirectoryEntry
 
 	// Calculate the total compressed size of all blobs
 	var totalBlobsCompressedSize int64
@@ -112,17 +102,11 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		totalBlobsCompressedSize += file.CompressedSize
 	}
 
-	// Calculate 1% threshold
-	thresholdSize := float64(totalBlobsCompressedSize) * CompressedSizePercentageThreshold
-
-	// Get files from default branch for comparison
-	defaultBranch, defaultBranchError := git.GetDefaultBranch()
-	hasDefaultBranch := defaultBranchError == nil
-	defaultBranchFiles, defaultBranchFilesError := git.GetBranchFiles(defaultBranch)
+	thresholdSize := float64(totalBlobsCompressedSize) * thresholdPercent / 100
 
 	// Check if path exists in default branch
 	pathExistsInDefaultBranch := func(path string) bool {
-		if defaultBranchFilesError != nil || defaultBranchFiles == nil {
+		if defaultBranchError != nil || defaultBranchFiles == nil {
 			return true
 		}
 		return defaultBranchFiles[path]
@@ -130,7 +114,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 
 	// Check if directory exists in default branch
 	directoryExistsInDefaultBranch := func(dirPath string) bool {
-		if defaultBranchFilesError != nil || defaultBranchFiles == nil {
+		if defaultBranchError != nil || defaultBranchFiles == nil {
 			return true
 		}
 
@@ -152,8 +136,8 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		pathParts := strings.Split(file.Path, "/")
 		currentPath := ""
 
-		// Create entries for all directories in the path (up to MaxDirectoryDepth levels)
-		for level := 0; level < len(pathParts)-1 && level < MaxDirectoryDepth; level++ {
+		// Create entries for all directories in the path (up to maxDepth levels)
+		for level := 0; level < len(pathParts)-1 && level < maxDepth; level++ {
 			if currentPath == "" {
 				currentPath = pathParts[level]
 			} else {
@@ -162,8 +146,8 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 
 			if _, exists := directoryStats[currentPath]; !exists {
 				directoryStats[currentPath] = &entry{
-					Path:                  pathParts[level],
-					FullPath:              currentPath,
+					Name:                  pathParts[level],
+					Path:                  currentPath,
 					Level:                 level + 1,
 					IsFile:                false,
 					ExistsInDefaultBranch: directoryExistsInDefaultBranch(currentPath),
@@ -177,7 +161,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		}
 	}
 
-	// Collect significant entries (directories and files >= 1%)
+	// Collect significant entries (directories and files at or above the threshold)
 	var significantEntries []*entry
 
 	// Add significant directories
@@ -190,15 +174,12 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 	// Add significant files
 	for _, file := range files {
 		if float64(file.CompressedSize) >= thresholdSize {
-			// Calculate the level based on path depth (limited to MaxDirectoryDepth)
-			level := strings.Count(file.Path, "/") + 1
-			if level > MaxDirectoryDepth {
-				level = MaxDirectoryDepth
-			}
+			// Calculate the level based on path depth (limited to maxDepth)
+			level := min(strings.Count(file.Path, "/")+1, maxDepth)
 
 			fileEntry := &entry{
-				Path:                  filepath.Base(file.Path),
-				FullPath:              file.Path,
+				Name:                  filepath.Base(file.Path),
+				Path:                  file.Path,
 				Blobs:                 file.Blobs,
 				CompressedSize:        file.CompressedSize,
 				Level:                 level,
@@ -217,7 +198,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		if entry.Level == 1 {
 			parentPath = ""
 		} else {
-			parentParts := strings.Split(entry.FullPath, "/")
+			parentParts := strings.Split(entry.Path, "/")
 			if entry.IsFile {
 				parentPath = strings.Join(parentParts[:len(parentParts)-1], "/")
 			} else {
@@ -237,7 +218,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 			if percentI != percentJ {
 				return percentI > percentJ
 			}
-			return group[i].FullPath < group[j].FullPath
+			return group[i].Path < group[j].Path
 		})
 	}
 
@@ -246,14 +227,13 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 
 	// Add root entry first
 	rootEntry := &entry{
+		Name:                  ".",
 		Path:                  ".",
-		FullPath:              ".",
 		Blobs:                 totalBlobs,
 		CompressedSize:        totalBlobsCompressedSize,
 		Level:                 0,
 		IsFile:                false,
 		ExistsInDefaultBranch: true,
-		treePrefix:            "",
 	}
 	sortedEntries = append(sortedEntries, rootEntry)
 
@@ -288,7 +268,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 
 	var buildTree func(level int, parentPath string, isLastAtLevel []bool)
 	buildTree = func(level int, parentPath string, isLastAtLevel []bool) {
-		if level > MaxTreeLevels { // Now max MaxTreeLevels levels (0-MaxDirectoryDepth, with 0 being root)
+		if level > maxDepth+1 { // Levels 0 to maxDepth, with 0 being root
 			return
 		}
 
@@ -303,7 +283,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		var files []*entry
 
 		for _, entry := range group {
-			if processedPaths[entry.FullPath] {
+			if processedPaths[entry.Path] {
 				continue // Skip already processed entries
 			}
 
@@ -318,7 +298,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		allEntries := append(directories, files...)
 
 		for i, entry := range allEntries {
-			if processedPaths[entry.FullPath] {
+			if processedPaths[entry.Path] {
 				continue
 			}
 
@@ -332,14 +312,14 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 			newIsLastAtLevel[level] = isLast
 
 			// Create tree prefix for this entry (adjust level for display)
-			entry.treePrefix = createTreePrefix(level+1, newIsLastAtLevel)
+			entry.TreePrefix = createTreePrefix(level+1, newIsLastAtLevel)
 
 			sortedEntries = append(sortedEntries, entry)
-			processedPaths[entry.FullPath] = true
+			processedPaths[entry.Path] = true
 
 			// If this is a directory, process its children
 			if !entry.IsFile {
-				buildTree(level+1, entry.FullPath, newIsLastAtLevel)
+				buildTree(level+1, entry.Path, newIsLastAtLevel)
 			}
 		}
 	}
@@ -347,21 +327,41 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 	// Start processing from level 1 (root level)
 	buildTree(1, "", []bool{})
 
-	// Print header
-	progress.StopSectionSpinner()
-	fmt.Println()
-	fmt.Println("Showing directories and files that contribute more than 1% of total on-disk size.")
-
-	var missingPathsError error = nil
+	report := models.DirectoryReport{
+		Threshold:           thresholdPercent,
+		TotalBlobs:          totalBlobs,
+		TotalCompressedSize: totalBlobsCompressedSize,
+	}
+	if defaultBranch != "" {
+		report.DefaultBranch = defaultBranch
+	}
 	if defaultBranchError != nil {
-		missingPathsError = defaultBranchError
-	} else if defaultBranchFilesError != nil {
-		missingPathsError = defaultBranchFilesError
+		report.DefaultBranchError = defaultBranchError.Error()
 	}
+	for _, entry := range sortedEntries {
+		report.Entries = append(report.Entries, *entry)
+	}
+
+	return report
+}
+
+// PrintLargestDirectoriesSectionTitle prints the largest directories section header
+func PrintLargestDirectoriesSectionTitle() {
+	fmt.Println("\nLARGEST DIRECTORIES ####################################################################################################")
+}
+
+// PrintLargestDirectories prints the directories and files at or above the threshold of the report
+func PrintLargestDirectories(report models.DirectoryReport) {
+	hasDefaultBranch := report.DefaultBranch != ""
+	totalBlobs := report.TotalBlobs
+	totalBlobsCompressedSize := report.TotalCompressedSize
 
-	if missingPathsError != nil {
+	fmt.Println()
+	fmt.Printf("Showing directories and files that contribute more than %g%% of total on-disk size.\n", report.Threshold)
+
+	if report.DefaultBranchError != "" {
 		fmt.Println()
-		fmt.Printf("Warning: Could not determine moved, renamed or removed files and directories: %s\n", missingPathsError)
+		fmt.Printf("Warning: Could not determine moved, renamed or removed files and directories: %s\n", report.DefaultBranchError)
 	}
 
 	fmt.Println()
@@ -379,7 +379,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 	var footnotes []Footnote
 
 	// Print significant entries
-	for _, entry := range sortedEntries {
+	for _, entry := range report.Entries {
 		// Calculate percentages
 		percentBlobs := 0.0
 		percentSize := 0.0
@@ -391,10 +391,10 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 		}
 
 		// Create indentation based on tree structure
-		prefix := entry.treePrefix
+		prefix := entry.TreePrefix
 
 		// Add asterisk if not in default branch
-		displayName := entry.Path // Use just the name at this level, not full path
+		displayName := entry.Name // Use just the name at this level, not full path
 
 		// Add trailing slash for directories
 		if !entry.IsFile {
@@ -451,7 +451,7 @@ func PrintLargestDirectories(files []models.FileInformation, totalBlobs int, tot
 	// Add footnote explaining the asterisk meaning
 	if hasDefaultBranch && showFootnote {
 		fmt.Println()
-		fmt.Printf("* File or directory not present in latest commit of %s branch (moved, renamed or removed)\n", defaultBranch)
+		fmt.Printf("* File or directory not present in latest commit of %s branch (moved, renamed or removed)\n", report.DefaultBranch)
 	}
 
 	// Print footnotes for truncated paths
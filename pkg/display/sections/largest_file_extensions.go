@@ -3,111 +3,98 @@ package sections
 import (
 	"fmt"
 	"git-metrics/pkg/models"
-	"git-metrics/pkg/progress"
 	"git-metrics/pkg/utils"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 )
 
-// PrintTopFileExtensions prints the top file extensions by size
-func PrintTopFileExtensions(blobs []models.FileInformation, totalBlobs int, totalSize int64) {
-	fmt.Println("\nLARGEST FILE EXTENSIONS ################################################################################################")
-	progress.StartSectionSpinner()
-
-	extensionStatistics := make(map[string]struct {
-		compressedSize   int64
-		uncompressedSize int64
-		filesCount       int
-		blobsCount       int
-	})
+// BuildFileExtensions aggregates blobs by file extension and returns the extensions
+// with the largest on-disk size (up to limit) together with the totals over all extensions
+func BuildFileExtensions(blobs []models.FileInformation, limit int) models.FileExtensionReport {
+	extensionStatistics := make(map[string]models.FileExtensionStatistics)
 	for _, blob := range blobs {
 		extension := filepath.Ext(blob.Path)
 		if extension == "" {
 			extension = "No Extension"
 		}
 		statistics := extensionStatistics[extension]
-		statistics.compressedSize += blob.CompressedSize
-		statistics.uncompressedSize += blob.UncompressedSize
-		statistics.filesCount++
-		statistics.blobsCount += blob.Blobs
+		statistics.Extension = extension
+		statistics.CompressedSize += blob.CompressedSize
+		statistics.UncompressedSize += blob.UncompressedSize
+		statistics.Files++
+		statistics.Blobs += blob.Blobs
 		extensionStatistics[extension] = statistics
 	}
 
-	// Create a slice for sorting.
-	type extensionStatistic struct {
-		extension        string
-		compressedSize   int64
-		uncompressedSize int64
-		filesCount       int
-		blobsCount       int
-	}
-	var statistics []extensionStatistic
-	for extension, statistic := range extensionStatistics {
-		statistics = append(statistics, extensionStatistic{
-			extension:        extension,
-			compressedSize:   statistic.compressedSize,
-			uncompressedSize: statistic.uncompressedSize,
-			filesCount:       statistic.filesCount,
-			blobsCount:       statistic.blobsCount,
-		})
+	// Create a slice for sorting and calculate totals from all extensions
+	report := models.FileExtensionReport{ExtensionCount: len(extensionStatistics)}
+	var statistics []models.FileExtensionStatistics
+	for _, statistic := range extensionStatistics {
+		statistics = append(statistics, statistic)
+		report.Total.Files += statistic.Files
+		report.Total.Blobs += statistic.Blobs
+		report.Total.CompressedSize += statistic.CompressedSize
+		report.Total.UncompressedSize += statistic.UncompressedSize
 	}
 	sort.Slice(statistics, func(i, j int) bool {
-		return statistics[i].compressedSize > statistics[j].compressedSize
+		if statistics[i].CompressedSize != statistics[j].CompressedSize {
+			return statistics[i].CompressedSize > statistics[j].CompressedSize
+		}
+		return statistics[i].Extension < statistics[j].Extension
 	})
 
-	// Calculate totals from all extensions first
-	var totalExtFilesCount, totalExtBlobsCount int
-	var totalExtCompressedSize, totalExtUncompressedSize int64
-	for _, statistic := range extensionStatistics {
-		totalExtFilesCount += statistic.filesCount
-		totalExtBlobsCount += statistic.blobsCount
-		totalExtCompressedSize += statistic.compressedSize
-		totalExtUncompressedSize += statistic.uncompressedSize
+	if len(statistics) > limit {
+		statistics = statistics[:limit]
 	}
+	report.Extensions = statistics
 
-	// Limit to top 10
-	if len(statistics) > 10 {
-		statistics = statistics[:10]
-	}
+	return report
+}
+
+// PrintFileExtensionsSectionTitle prints the largest file extensions section header
+func PrintFileExtensionsSectionTitle() {
+	fmt.Println("\nLARGEST FILE EXTENSIONS ################################################################################################")
+}
+
+// PrintTopFileExtensions prints the top file extensions by size
+func PrintTopFileExtensions(report models.FileExtensionReport, totalBlobs int) {
+	total := report.Total
 
 	// Track totals for displayed extensions (top 10)
 	var selectedFilesCount int
 	var selectedBlobsCount int
 	var selectedCompressedSize, selectedUncompressedSize int64
 
-	// Display results.
-	progress.StopSectionSpinner()
 	fmt.Println()
 	fmt.Println("Extension                          Files                  Blobs           Object size          On-disk size            ↓")
 	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
-	for _, statistic := range statistics {
-		percentageFiles := float64(statistic.filesCount) / float64(totalExtFilesCount) * 100
-		percentageBlobs := float64(statistic.blobsCount) / float64(totalBlobs) * 100
+	for _, statistic := range report.Extensions {
+		percentageFiles := float64(statistic.Files) / float64(total.Files) * 100
+		percentageBlobs := float64(statistic.Blobs) / float64(totalBlobs) * 100
 
 		// Calculate compression ratio (uncompressed / compressed)
 		var compressionRatio float64
-		if statistic.compressedSize > 0 {
-			compressionRatio = float64(statistic.uncompressedSize) / float64(statistic.compressedSize)
+		if statistic.CompressedSize > 0 {
+			compressionRatio = float64(statistic.UncompressedSize) / float64(statistic.CompressedSize)
 		}
 
 		// Calculate percentages relative to totals
-		percentageUncompressed := float64(statistic.uncompressedSize) / float64(totalExtUncompressedSize) * 100
-		percentageCompressed := float64(statistic.compressedSize) / float64(totalExtCompressedSize) * 100
+		percentageUncompressed := float64(statistic.UncompressedSize) / float64(total.UncompressedSize) * 100
+		percentageCompressed := float64(statistic.CompressedSize) / float64(total.CompressedSize) * 100
 
 		fmt.Printf("%-26s %13s %5.1f %%  %13s %5.1f %%  %12s %5.1f %%  %12s %5.1f %% %3.0fx\n",
-			statistic.extension,
-			utils.FormatNumber(statistic.filesCount), percentageFiles,
-			utils.FormatNumber(statistic.blobsCount), percentageBlobs,
-			utils.FormatSize(statistic.uncompressedSize), percentageUncompressed,
-			utils.FormatSize(statistic.compressedSize), percentageCompressed,
+			statistic.Extension,
+			utils.FormatNumber(statistic.Files), percentageFiles,
+			utils.FormatNumber(statistic.Blobs), percentageBlobs,
+			utils.FormatSize(statistic.UncompressedSize), percentageUncompressed,
+			utils.FormatSize(statistic.CompressedSize), percentageCompressed,
 			compressionRatio)
 
-		selectedFilesCount += statistic.filesCount
-		selectedBlobsCount += statistic.blobsCount
-		selectedCompressedSize += statistic.compressedSize
-		selectedUncompressedSize += statistic.uncompressedSize
+		selectedFilesCount += statistic.Files
+		selectedBlobsCount += statistic.Blobs
+		selectedCompressedSize += statistic.CompressedSize
+		selectedUncompressedSize += statistic.UncompressedSize
 	}
 
 	// Print separator and top 10 totals row
@@ -120,32 +107,32 @@ func PrintTopFileExtensions(blobs []models.FileInformation, totalBlobs int, tota
 	}
 
 	fmt.Printf("%-26s %13s %5.1f %%  %13s %5.1f %%  %12s %5.1f %%  %12s %5.1f %% %3.0fx\n",
-		fmt.Sprintf("├─ Top %s", utils.FormatNumber(len(statistics))),
+		fmt.Sprintf("├─ Top %s", utils.FormatNumber(len(report.Extensions))),
 		utils.FormatNumber(selectedFilesCount),
-		float64(selectedFilesCount)/float64(totalExtFilesCount)*100,
+		float64(selectedFilesCount)/float64(total.Files)*100,
 		utils.FormatNumber(selectedBlobsCount),
-		float64(selectedBlobsCount)/float64(totalExtBlobsCount)*100,
+		float64(selectedBlobsCount)/float64(total.Blobs)*100,
 		utils.FormatSize(selectedUncompressedSize),
-		float64(selectedUncompressedSize)/float64(totalExtUncompressedSize)*100,
+		float64(selectedUncompressedSize)/float64(total.UncompressedSize)*100,
 		utils.FormatSize(selectedCompressedSize),
-		float64(selectedCompressedSize)/float64(totalExtCompressedSize)*100,
+		float64(selectedCompressedSize)/float64(total.CompressedSize)*100,
 		selectedCompressionRatio)
 
 	// Print grand totals row using full totals
 	var totalCompressionRatio float64
-	if totalExtCompressedSize > 0 {
-		totalCompressionRatio = float64(totalExtUncompressedSize) / float64(totalExtCompressedSize)
+	if total.CompressedSize > 0 {
+		totalCompressionRatio = float64(total.UncompressedSize) / float64(total.CompressedSize)
 	}
 
 	fmt.Printf("%-26s %13s %5.1f %%  %13s %5.1f %%  %12s %5.1f %%  %12s %5.1f %% %3.0fx\n",
-		fmt.Sprintf("└─ Out of %s", utils.FormatNumber(len(extensionStatistics))),
-		utils.FormatNumber(totalExtFilesCount),
+		fmt.Sprintf("└─ Out of %s", utils.FormatNumber(report.ExtensionCount)),
+		utils.FormatNumber(total.Files),
 		100.0, // Always 100% for totals
-		utils.FormatNumber(totalExtBlobsCount),
+		utils.FormatNumber(total.Blobs),
 		100.0,
-		utils.FormatSize(totalExtUncompressedSize),
+		utils.FormatSize(total.UncompressedSize),
 		100.0,
-		utils.FormatSize(totalExtCompressedSize),
+		utils.FormatSize(total.CompressedSize),
 		100.0,
 		totalCompressionRatio)
 
@@ -160,42 +147,30 @@ const (
 		"############################################################################"
 	formatExtensionGrowthTableHeader = "Year     Extension (#1)         Growth     %   Extension (#2)         Growth     %   Extension (#3)         Growth     %   "
 
-	// Row formats for 3-column layout with percentages
-	formatThreeColumnRowExt = "%-6s │ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %%\n"
-	formatTwoColumnRowExt   = "%-6s │ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %%\n"
-	formatOneColumnRowExt   = "%-6s │ %-19s%10s%4.0f %%\n"
+	// Row formats for 3-column layout with percentages, the period column fits months and quarters like 2024-Q3
+	formatThreeColumnRowExt = "%-7s│ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %%\n"
+	formatTwoColumnRowExt   = "%-7s│ %-19s%10s%4.0f %% │ %-19s%10s%4.0f %%\n"
+	formatOneColumnRowExt   = "%-7s│ %-19s%10s%4.0f %%\n"
 
 	// Maximum extension name length
 	maxExtensionNameLength = 18
 )
 
-// extensionGrowthStats holds extension growth information
-type extensionGrowthStats struct {
-	extension string
-	growth    int64
-}
-
-// PrintFileExtensionGrowth displays the top 3 extensions with largest size growth per year
-func PrintFileExtensionGrowth(yearlyStatistics map[int]models.GrowthStatistics) {
-	if len(yearlyStatistics) < 2 {
-		return // Need at least 2 years to calculate growth
-	}
-
-	fmt.Println(formatExtensionGrowthHeader)
-	progress.StartSectionSpinner()
-
-	// Get years and sort them
-	var years []int
-	for year := range yearlyStatistics {
-		years = append(years, year)
+// BuildFileExtensionGrowth calculates the extensions with the largest on-disk size growth
+// (up to limit) for each period after the first one
+func BuildFileExtensionGrowth(periodStatistics map[models.Period]models.GrowthStatistics, limit int) []models.FileExtensionGrowthReport {
+	// Get periods and sort them
+	var periods []models.Period
+	for period := range periodStatistics {
+		periods = append(periods, period)
 	}
-	sort.Ints(years)
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
 
-	// Calculate extension statistics for each year
-	yearlyExtensionStats := make(map[int]map[string]int64)
+	// Calculate extension statistics for each period
+	periodExtensionStats := make(map[models.Period]map[string]int64)
 
-	for _, year := range years {
-		stats := yearlyStatistics[year]
+	for _, period := range periods {
+		stats := periodStatistics[period]
 		extensionSizes := make(map[string]int64)
 
 		for _, blob := range stats.LargestFiles {
@@ -206,51 +181,79 @@ func PrintFileExtensionGrowth(yearlyStatistics map[int]models.GrowthStatistics)
 			extensionSizes[extension] += blob.CompressedSize
 		}
 
-		yearlyExtensionStats[year] = extensionSizes
+		periodExtensionStats[period] = extensionSizes
 	}
 
-	progress.StopSectionSpinner()
+	// Calculate growth for each period (starting from second period)
+	var reports []models.FileExtensionGrowthReport
+	for i := 1; i < len(periods); i++ {
+		currentPeriod := periods[i]
+		previousPeriod := periods[i-1]
 
-	fmt.Println()
-	fmt.Println(formatExtensionGrowthTableHeader)
-	fmt.Println(strings.Repeat("-", 120))
-
-	// Display growth for each year (starting from second year)
-	for i := 1; i < len(years); i++ {
-		currentYear := years[i]
-		previousYear := years[i-1]
-
-		currentStats := yearlyExtensionStats[currentYear]
-		previousStats := yearlyExtensionStats[previousYear]
+		currentStats := periodExtensionStats[currentPeriod]
+		previousStats := periodExtensionStats[previousPeriod]
 
 		// Calculate growth for each extension
-		var growthStats []extensionGrowthStats
-		var totalYearlyDelta int64
+		var growthStats []models.FileExtensionGrowth
+		var totalPeriodDelta int64
 
 		for extension, currentSize := range currentStats {
 			previousSize := previousStats[extension] // will be 0 if extension didn't exist previously
 			growth := currentSize - previousSize
 			if growth > 0 {
-				growthStats = append(growthStats, extensionGrowthStats{
-					extension: extension,
-					growth:    growth,
+				growthStats = append(growthStats, models.FileExtensionGrowth{
+					Extension: extension,
+					Growth:    growth,
 				})
-				totalYearlyDelta += growth
+				totalPeriodDelta += growth
 			}
 		}
 
 		// Sort by growth (descending)
 		sort.Slice(growthStats, func(i, j int) bool {
-			return growthStats[i].growth > growthStats[j].growth
+			if growthStats[i].Growth != growthStats[j].Growth {
+				return growthStats[i].Growth > growthStats[j].Growth
+			}
+			return growthStats[i].Extension < growthStats[j].Extension
 		})
 
-		// Limit to top 3
-		if len(growthStats) > 3 {
-			growthStats = growthStats[:3]
+		// Limit to top entries
+		if len(growthStats) > limit {
+			growthStats = growthStats[:limit]
 		}
 
-		// Display the row
-		displayExtensionGrowthRow(strconv.Itoa(currentYear), growthStats, totalYearlyDelta)
+		for index := range growthStats {
+			if totalPeriodDelta > 0 {
+				growthStats[index].Percent = float64(growthStats[index].Growth) / float64(totalPeriodDelta) * 100
+			}
+		}
+
+		reports = append(reports, models.FileExtensionGrowthReport{
+			Period:     currentPeriod,
+			Extensions: growthStats,
+		})
+	}
+
+	return reports
+}
+
+// PrintFileExtensionGrowthSectionTitle prints the file extension growth section header
+func PrintFileExtensionGrowthSectionTitle() {
+	fmt.Println(formatExtensionGrowthHeader)
+}
+
+// PrintFileExtensionGrowth displays the top 3 extensions with largest size growth per period
+func PrintFileExtensionGrowth(reports []models.FileExtensionGrowthReport, interval models.Interval) {
+	if len(reports) == 0 {
+		return // Need at least 2 periods to calculate growth
+	}
+
+	fmt.Println()
+	fmt.Println(periodColumnHeader(formatExtensionGrowthTableHeader, interval))
+	fmt.Println(strings.Repeat("-", 120))
+
+	for _, report := range reports {
+		displayExtensionGrowthRow(report.Period.String(), report.Extensions)
 	}
 }
 
@@ -263,7 +266,7 @@ func truncateExtensionName(name string) string {
 }
 
 // displayExtensionGrowthRow displays a row of extensions with their growth and percentages
-func displayExtensionGrowthRow(yearStr string, growthStats []extensionGrowthStats, totalYearlyDelta int64) {
+func displayExtensionGrowthRow(periodLabel string, growthStats []models.FileExtensionGrowth) {
 	// Prepare data arrays for each column
 	var extensions [3]string
 	var growths [3]string
@@ -271,11 +274,9 @@ func displayExtensionGrowthRow(yearStr string, growthStats []extensionGrowthStat
 
 	for i := 0; i < 3; i++ {
 		if i < len(growthStats) {
-			extensions[i] = truncateExtensionName(growthStats[i].extension)
-			growths[i] = "+" + strings.TrimSpace(utils.FormatSize(growthStats[i].growth))
-			if totalYearlyDelta > 0 {
-				percentages[i] = float64(growthStats[i].growth) / float64(totalYearlyDelta) * 100
-			}
+			extensions[i] = truncateExtensionName(growthStats[i].Extension)
+			growths[i] = "+" + strings.TrimSpace(utils.FormatSize(growthStats[i].Growth))
+			percentages[i] = growthStats[i].Percent
 		} else {
 			extensions[i] = ""
 			growths[i] = ""
@@ -287,18 +288,18 @@ func displayExtensionGrowthRow(yearStr string, growthStats []extensionGrowthStat
 	switch len(growthStats) {
 	case 3:
 		fmt.Printf(formatThreeColumnRowExt,
-			yearStr,
+			periodLabel,
 			extensions[0], growths[0], percentages[0],
 			extensions[1], growths[1], percentages[1],
 			extensions[2], growths[2], percentages[2])
 	case 2:
 		fmt.Printf(formatTwoColumnRowExt,
-			yearStr,
+			periodLabel,
 			extensions[0], growths[0], percentages[0],
 			extensions[1], growths[1], percentages[1])
 	case 1:
 		fmt.Printf(formatOneColumnRowExt,
-			yearStr,
+			periodLabel,
 			extensions[0], growths[0], percentages[0])
 	}
 }
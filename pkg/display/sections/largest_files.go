@@ -4,10 +4,45 @@ import (
 	"fmt"
 	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
+	"sort"
 )
 
+// BuildLargestFiles selects the files with the largest on-disk size (up to limit)
+// together with the totals over all files
+func BuildLargestFiles(files []models.FileInformation, totalBlobs int, limit int) models.LargestFilesReport {
+	report := models.LargestFilesReport{
+		TotalBlobs: totalBlobs,
+		TotalFiles: len(files),
+	}
+
+	largestFiles := make([]models.FileInformation, len(files))
+	copy(largestFiles, files)
+	sort.Slice(largestFiles, func(i, j int) bool {
+		if largestFiles[i].CompressedSize != largestFiles[j].CompressedSize {
+			return largestFiles[i].CompressedSize > largestFiles[j].CompressedSize
+		}
+		return largestFiles[i].Path < largestFiles[j].Path
+	})
+
+	for _, file := range largestFiles {
+		report.TotalCompressedSize += file.CompressedSize
+	}
+
+	if len(largestFiles) > limit {
+		largestFiles = largestFiles[:limit]
+	}
+	report.Files = largestFiles
+
+	return report
+}
+
 // PrintLargestFiles prints information about the largest files
-func PrintLargestFiles(files []models.FileInformation, totalFilesSize int64, totalBlobs int, totalFiles int) {
+func PrintLargestFiles(report models.LargestFilesReport) {
+	files := report.Files
+	totalFilesSize := report.TotalCompressedSize
+	totalBlobs := report.TotalBlobs
+	totalFiles := report.TotalFiles
+
 	fmt.Println("\nLARGEST FILES ##########################################################################################################")
 	fmt.Println()
 	fmt.Println("      Blobs          On-disk size           Path")
@@ -26,7 +61,7 @@ func PrintLargestFiles(files []models.FileInformation, totalFilesSize int64, tot
 		percentageBlobs := float64(file.Blobs) / float64(totalBlobs) * 100
 
 		// Use CreatePathFootnote for consistent truncation and footnote logic
-		result := CreatePathFootnote(file.Path, 76, len(footnotes))
+		result := CreatePathFootnote(file.Path, PathColumnWidth, len(footnotes))
 		displayPath := result.DisplayPath
 		if result.Index > 0 {
 			footnotes = append(footnotes, Footnote{
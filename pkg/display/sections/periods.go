@@ -0,0 +1,17 @@
+package sections
+
+import (
+	"strings"
+
+	"git-metrics/pkg/models"
+)
+
+// periodColumnHeader replaces the Year title of the first column of a table header with the title
+// of the interval, keeping the following columns aligned
+func periodColumnHeader(header string, interval models.Interval) string {
+	rest := strings.TrimPrefix(header, "Year")
+	columns := strings.TrimLeft(rest, " ")
+	title := interval.Title()
+	spaces := max(len("Year")+len(rest)-len(columns)-len(title), 1)
+	return title + strings.Repeat(" ", spaces) + columns
+}
@@ -0,0 +1,193 @@
+package sections
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// maxReportedBlobs limits the blobs listed as violations of the maximum blob size
+const maxReportedBlobs = 10
+
+// EvaluatePolicy checks the report and the files of the analyzed history against the limits of the
+// policy and returns a violation for each exceeded limit
+func EvaluatePolicy(policy models.Policy, report models.Report, files []models.FileInformation) []models.PolicyViolation {
+	var violations []models.PolicyViolation
+
+	if policy.MaxDiskSize > 0 && report.Repository.CompressedSize > policy.MaxDiskSize {
+		violations = append(violations, models.PolicyViolation{
+			Rule: models.MaxDiskSizeRule,
+			Message: fmt.Sprintf("On-disk size of %s exceeds the limit of %s",
+				strings.TrimSpace(utils.FormatSize(report.Repository.CompressedSize)), strings.TrimSpace(utils.FormatSize(policy.MaxDiskSize))),
+		})
+	}
+
+	if policy.MaxBlobSize > 0 {
+		violations = append(violations, blobSizeViolations(policy.MaxBlobSize, files)...)
+	}
+
+	if policy.MaxConcernLevel != "" {
+		allowed := slices.Index(models.ConcernLevels, policy.MaxConcernLevel)
+		highest := map[string]models.ConcernProjection{}
+		for _, projection := range report.ConcernProjections {
+			if projection.Reached && slices.Index(models.ConcernLevels, projection.Level) > allowed {
+				highest[projection.Metric] = projection
+			}
+		}
+		for _, metric := range models.ConcernMetrics {
+			if projection, exists := highest[metric]; exists {
+				violations = append(violations, models.PolicyViolation{
+					Rule: models.MaxConcernLevelRule,
+					Message: fmt.Sprintf("%s of %s is %s from %s, the highest level allowed is %s",
+						concernMetricNames[metric], formatConcernValue(metric, projection.Value), projection.Level,
+						formatConcernValue(metric, projection.Threshold), policy.MaxConcernLevel),
+				})
+			}
+		}
+	}
+
+	if policy.MaxGrowthPercent > 0 {
+		lastComplete := report.Interval.PeriodOf(report.Repository.AsOf).Add(-1)
+		for _, statistics := range report.Growth {
+			before := statistics.Compressed - statistics.CompressedDelta
+			if statistics.Period != lastComplete || before <= 0 {
+				continue
+			}
+			if growth := float64(statistics.CompressedDelta) / float64(before) * 100; growth > policy.MaxGrowthPercent {
+				violations = append(violations, models.PolicyViolation{
+					Rule: models.MaxGrowthRule,
+					Message: fmt.Sprintf("On-disk size grew by %.1f%% in %s, more than the limit of %g%%",
+						growth, statistics.Period, policy.MaxGrowthPercent),
+				})
+			}
+		}
+	}
+
+	if len(policy.ForbiddenExtensions) > 0 {
+		violations = append(violations, forbiddenExtensionViolations(policy.ForbiddenExtensions, files)...)
+	}
+
+	return violations
+}
+
+// EvaluateRangePolicy checks the objects a range adds against the limits of the policy: the maximum
+// disk size limits the on-disk size the range adds and the maximum concern level the level of its
+// largest blob. The growth is not checked, as a range has no periods.
+func EvaluateRangePolicy(policy models.Policy, report models.RangeReport, files []models.FileInformation, thresholds models.ConcernThresholds) []models.PolicyViolation {
+	var violations []models.PolicyViolation
+
+	if policy.MaxDiskSize > 0 && report.Compressed > policy.MaxDiskSize {
+		violations = append(violations, models.PolicyViolation{
+			Rule: models.MaxDiskSizeRule,
+			Message: fmt.Sprintf("The range adds %s on disk, more than the limit of %s",
+				strings.TrimSpace(utils.FormatSize(report.Compressed)), strings.TrimSpace(utils.FormatSize(policy.MaxDiskSize))),
+		})
+	}
+
+	if policy.MaxBlobSize > 0 {
+		violations = append(violations, blobSizeViolations(policy.MaxBlobSize, files)...)
+	}
+
+	if policy.MaxConcernLevel != "" && len(report.LargestBlobs) > 0 {
+		largest := report.LargestBlobs[0]
+		threshold := thresholds[models.LargestBlobConcernMetric]
+		level, from := models.UnconcerningConcernLevel, int64(0)
+		switch {
+		case largest.LargestBlob >= threshold.Concerning:
+			level, from = models.ConcerningConcernLevel, threshold.Concerning
+		case largest.LargestBlob >= threshold.OnRoad:
+			level, from = models.OnRoadConcernLevel, threshold.OnRoad
+		}
+		if slices.Index(models.ConcernLevels, level) > slices.Index(models.ConcernLevels, policy.MaxConcernLevel) {
+			violations = append(violations, models.PolicyViolation{
+				Rule: models.MaxConcernLevelRule,
+				Message: fmt.Sprintf("Blob of %s with %s is %s from %s, the highest level allowed is %s",
+					largest.Path, strings.TrimSpace(utils.FormatSize(largest.LargestBlob)), level,
+					strings.TrimSpace(utils.FormatSize(from)), policy.MaxConcernLevel),
+			})
+		}
+	}
+
+	if len(policy.ForbiddenExtensions) > 0 {
+		violations = append(violations, forbiddenExtensionViolations(policy.ForbiddenExtensions, files)...)
+	}
+
+	return violations
+}
+
+// blobSizeViolations returns a violation for each file with a blob larger than the limit, largest
+// first. Beyond maxReportedBlobs files, the remaining ones are summed up in a single violation.
+func blobSizeViolations(limit int64, files []models.FileInformation) []models.PolicyViolation {
+	var largeFiles []models.FileInformation
+	for _, file := range files {
+		if file.LargestBlob > limit {
+			largeFiles = append(largeFiles, file)
+		}
+	}
+	sort.Slice(largeFiles, func(i, j int) bool {
+		if largeFiles[i].LargestBlob != largeFiles[j].LargestBlob {
+			return largeFiles[i].LargestBlob > largeFiles[j].LargestBlob
+		}
+		return largeFiles[i].Path < largeFiles[j].Path
+	})
+
+	var violations []models.PolicyViolation
+	for i, file := range largeFiles {
+		if i == maxReportedBlobs {
+			violations = append(violations, models.PolicyViolation{
+				Rule:    models.MaxBlobSizeRule,
+				Message: fmt.Sprintf("Blobs of %d more files exceed the limit of %s", len(largeFiles)-i, strings.TrimSpace(utils.FormatSize(limit))),
+			})
+			break
+		}
+		violations = append(violations, models.PolicyViolation{
+			Rule: models.MaxBlobSizeRule,
+			Message: fmt.Sprintf("Blob of %s with %s exceeds the limit of %s",
+				file.Path, strings.TrimSpace(utils.FormatSize(file.LargestBlob)), strings.TrimSpace(utils.FormatSize(limit))),
+		})
+	}
+	return violations
+}
+
+// forbiddenExtensionViolations returns a violation for each forbidden extension files have
+func forbiddenExtensionViolations(forbiddenExtensions []string, files []models.FileInformation) []models.PolicyViolation {
+	var violations []models.PolicyViolation
+	for _, statistics := range BuildFileExtensions(files, len(files)).Extensions {
+		if slices.ContainsFunc(forbiddenExtensions, func(forbidden string) bool { return strings.EqualFold(forbidden, statistics.Extension) }) {
+			violations = append(violations, models.PolicyViolation{
+				Rule: models.ForbiddenExtensionRule,
+				Message: fmt.Sprintf("Files with the forbidden extension %s: %s with %s on disk",
+					statistics.Extension, utils.FormatNumber(statistics.Files), strings.TrimSpace(utils.FormatSize(statistics.CompressedSize))),
+			})
+		}
+	}
+	return violations
+}
+
+// PrintPolicySectionTitle prints the section title banner for the policy check
+func PrintPolicySectionTitle() {
+	fmt.Println("\nPOLICY CHECK ###########################################################################################################")
+}
+
+// DisplayPolicyViolations prints the violations of the policy or that the repository complies with it
+func DisplayPolicyViolations(policy models.Policy, violations []models.PolicyViolation) {
+	rules := policy.Rules()
+	fmt.Println()
+	if len(violations) == 0 {
+		fmt.Printf("The repository complies with the policy, rules checked: %s\n", strings.Join(rules, ", "))
+		return
+	}
+
+	fmt.Println("Rule                Violation")
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	for _, violation := range violations {
+		fmt.Printf("%-19s %s\n", violation.Rule, violation.Message)
+	}
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	fmt.Println()
+	fmt.Printf("Violations found: %d, rules checked: %s\n", len(violations), strings.Join(rules, ", "))
+}
@@ -0,0 +1,89 @@
+package sections
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"git-metrics/pkg/models"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	const megabyte = 1000 * 1000
+	report := models.Report{
+		Interval:   models.YearInterval,
+		Repository: &models.RepositoryInformation{CompressedSize: 1500 * megabyte, AsOf: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		Growth: []models.GrowthStatistics{
+			{Period: year(2023), Compressed: 1000 * megabyte, CompressedDelta: 200 * megabyte},
+			{Period: year(2024), Compressed: 1300 * megabyte, CompressedDelta: 300 * megabyte},
+		},
+		ConcernProjections: []models.ConcernProjection{
+			{Metric: models.DiskSizeConcernMetric, Level: models.OnRoadConcernLevel, Threshold: 1000 * megabyte, Value: 1500 * megabyte, Reached: true},
+			{Metric: models.DiskSizeConcernMetric, Level: models.ConcerningConcernLevel, Threshold: 20000 * megabyte, Value: 1500 * megabyte},
+		},
+	}
+	files := []models.FileInformation{
+		{Path: "assets/video.mp4", LargestBlob: 200 * megabyte, CompressedSize: 190 * megabyte},
+		{Path: "backup.ZIP", LargestBlob: 50 * megabyte, CompressedSize: 50 * megabyte},
+		{Path: "main.go", LargestBlob: 1000, CompressedSize: 500},
+	}
+	rules := func(violations []models.PolicyViolation) string {
+		var names []string
+		for _, violation := range violations {
+			names = append(names, violation.Rule)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	policy := models.Policy{
+		MaxDiskSize:         2000 * megabyte,
+		MaxBlobSize:         100 * megabyte,
+		MaxConcernLevel:     models.OnRoadConcernLevel,
+		MaxGrowthPercent:    25,
+		ForbiddenExtensions: []string{".zip"},
+	}
+	violations := EvaluatePolicy(policy, report, files)
+	// The disk size and concern level are within the limits, 2024 grew by 30 %
+	if expected := "max-blob-size, max-growth, forbid-extension"; rules(violations) != expected {
+		t.Fatalf("expected violations of %s, got %+v", expected, violations)
+	}
+	if !strings.Contains(violations[0].Message, "assets/video.mp4") || !strings.Contains(violations[1].Message, "30.0%") || !strings.Contains(violations[2].Message, ".ZIP") {
+		t.Errorf("expected the violations to name the file, growth and extension, got %+v", violations)
+	}
+
+	strict := models.Policy{MaxDiskSize: 1000 * megabyte, MaxConcernLevel: models.UnconcerningConcernLevel}
+	if expected := "max-disk-size, max-concern-level"; rules(EvaluatePolicy(strict, report, files)) != expected {
+		t.Errorf("expected violations of %s, got %+v", expected, EvaluatePolicy(strict, report, files))
+	}
+
+	output := captureOutput(func() { DisplayPolicyViolations(policy, nil) })
+	if !strings.Contains(output, "The repository complies with the policy, rules checked: max-disk-size, max-blob-size, max-concern-level, max-growth, forbid-extension") {
+		t.Errorf("expected the checked rules, got %s", output)
+	}
+}
+
+func TestEvaluateRangePolicy(t *testing.T) {
+	const megabyte = 1000 * 1000
+	files := []models.FileInformation{
+		{Path: "assets/video.mp4", LargestBlob: 300 * megabyte, CompressedSize: 290 * megabyte},
+		{Path: "main.go", LargestBlob: 1000, CompressedSize: 500},
+	}
+	report := BuildRangeReport([]string{"refs/heads/main"}, models.GrowthStatistics{Commits: 2, Blobs: 2, Compressed: 290*megabyte + 500, LargestFiles: files}, 10)
+	thresholds := models.ConcernProfiles["hosted"]
+
+	// The video is concerning from 100 MB with the hosted profile
+	violations := EvaluateRangePolicy(models.Policy{MaxDiskSize: 1000 * megabyte, MaxConcernLevel: models.OnRoadConcernLevel}, report, files, thresholds)
+	if len(violations) != 1 || violations[0].Rule != models.MaxConcernLevelRule || !strings.Contains(violations[0].Message, "assets/video.mp4") {
+		t.Errorf("expected the video to be too concerning, got %+v", violations)
+	}
+
+	violations = EvaluateRangePolicy(models.Policy{MaxDiskSize: 100 * megabyte, MaxBlobSize: 100 * megabyte, ForbiddenExtensions: []string{".mp4"}}, report, files, thresholds)
+	if len(violations) != 3 || violations[0].Rule != models.MaxDiskSizeRule || violations[1].Rule != models.MaxBlobSizeRule || violations[2].Rule != models.ForbiddenExtensionRule {
+		t.Errorf("expected the added size, the video blob and its extension to violate the policy, got %+v", violations)
+	}
+
+	output := captureOutput(func() { DisplayRange(report) })
+	if !strings.Contains(output, "Range                      refs/heads/main") || !strings.Contains(output, "assets/video.mp4") {
+		t.Errorf("expected the updated ref and the largest new blob, got %s", output)
+	}
+}
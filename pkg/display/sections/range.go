@@ -0,0 +1,70 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// BuildRangeReport builds the report of the objects a range adds from their totals and files, which
+// are sorted by their largest blob, and keeps the files with the largest blobs up to the limit
+func BuildRangeReport(names []string, statistics models.GrowthStatistics, limit int) models.RangeReport {
+	report := models.RangeReport{
+		Names:        names,
+		Commits:      statistics.Commits,
+		Trees:        statistics.Trees,
+		Blobs:        statistics.Blobs,
+		Compressed:   statistics.Compressed,
+		Uncompressed: statistics.Uncompressed,
+		LargestBlobs: []models.FileInformation{},
+	}
+	if report.Names == nil {
+		report.Names = []string{}
+	}
+	report.LargestBlobs = append(report.LargestBlobs, statistics.LargestFiles[:min(limit, len(statistics.LargestFiles))]...)
+	return report
+}
+
+// PrintRangeSectionTitle prints the section title banner for the objects of a range
+func PrintRangeSectionTitle() {
+	fmt.Println("\nRANGE ##################################################################################################################")
+	fmt.Println()
+}
+
+// DisplayRange prints the objects the range adds and the files with the largest new blobs
+func DisplayRange(report models.RangeReport) {
+	names := strings.Join(report.Names, ", ")
+	if names == "" {
+		names = "no updated refs"
+	}
+	fmt.Printf("Range                      %s\n", names)
+	fmt.Printf("Commits                    %s\n", utils.FormatNumber(report.Commits))
+	fmt.Printf("Trees                      %s\n", utils.FormatNumber(report.Trees))
+	fmt.Printf("Blobs                      %s\n", utils.FormatNumber(report.Blobs))
+	fmt.Printf("Object size                %s\n", strings.TrimSpace(utils.FormatSize(report.Uncompressed)))
+	fmt.Printf("On-disk size               %s\n", strings.TrimSpace(utils.FormatSize(report.Compressed)))
+	if len(report.LargestBlobs) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("   Largest blob          On-disk size   Path")
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	var footnotes []Footnote
+	for _, file := range report.LargestBlobs {
+		result := CreatePathFootnote(file.Path, 80, len(footnotes))
+		if result.Index > 0 {
+			footnotes = append(footnotes, Footnote{Index: result.Index, FullPath: result.FullPath})
+		}
+		fmt.Printf("%15s %21s   %s\n", utils.FormatSize(file.LargestBlob), utils.FormatSize(file.CompressedSize), result.DisplayPath)
+	}
+	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
+	if len(footnotes) > 0 {
+		fmt.Println()
+		for _, footnote := range footnotes {
+			fmt.Printf("[%d] %s\n", footnote.Index, footnote.FullPath)
+		}
+	}
+}
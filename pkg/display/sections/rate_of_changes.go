@@ -13,32 +13,45 @@ func PrintRateOfChangesSectionTitle() {
 	fmt.Println("\nRATE OF CHANGES ########################################################################################################")
 }
 
-// DisplayRateOfChanges displays commit rate statistics for the current branch
-func DisplayRateOfChanges(ratesByYear map[int]models.RateStatistics, defaultBranch string) {
-	if len(ratesByYear) == 0 {
+// BuildRateOfChanges converts commit rate statistics by period into a report sorted by period
+func BuildRateOfChanges(ratesByPeriod map[models.Period]models.RateStatistics, branch string) models.RateOfChangesReport {
+	report := models.RateOfChangesReport{Branch: branch}
+
+	// Sort periods
+	var periods []models.Period
+	for period := range ratesByPeriod {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
+
+	for _, period := range periods {
+		report.Periods = append(report.Periods, ratesByPeriod[period])
+	}
+
+	return report
+}
+
+// DisplayRateOfChanges displays commit rate statistics for the current branch or the analyzed refs
+func DisplayRateOfChanges(report models.RateOfChangesReport, interval models.Interval) {
+	if len(report.Periods) == 0 {
 		return
 	}
 
-	fmt.Printf("\nCommits to current branch (%s)\n\n", defaultBranch)
+	if report.Refs != "" {
+		fmt.Printf("\nCommits reachable from %s\n\n", report.Refs)
+	} else {
+		fmt.Printf("\nCommits to current branch (%s)\n\n", report.Branch)
+	}
 
 	// Table header with subcolumns
 	fmt.Println("                Commits         Active                Peak per day              Peak per hour            Peak per minute")
-	fmt.Println("Year           per year        Authors           P95    P99   P100          P95    P99   P100          P95    P99   P100")
+	fmt.Printf("%-7s%16s        Authors           P95    P99   P100          P95    P99   P100          P95    P99   P100\n", interval.Title(), "per "+interval.Noun())
 	fmt.Println("------------------------------------------------------------------------------------------------------------------------")
 
-	// Sort years
-	var years []int
-	for year := range ratesByYear {
-		years = append(years, year)
-	}
-	sort.Ints(years)
-
-	// Display statistics for each year
-	for _, year := range years {
-		stats := ratesByYear[year]
-
-		fmt.Printf("%-4d        %11s        %7d      │ %6d %6d %6d     │ %6d %6d %6d     │ %6d %6d %6d\n",
-			stats.Year,
+	// Display statistics for each period
+	for _, stats := range report.Periods {
+		fmt.Printf("%-11s %11s        %7d      │ %6d %6d %6d     │ %6d %6d %6d     │ %6d %6d %6d\n",
+			stats.Period,
 			utils.FormatNumber(stats.TotalCommits),
 			stats.ActiveAuthors,
 			stats.DailyPeakP95, stats.DailyPeakP99, stats.DailyPeakP100,
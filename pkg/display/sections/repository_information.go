@@ -0,0 +1,43 @@
+package sections
+
+import (
+	"fmt"
+	"git-metrics/pkg/models"
+)
+
+// PrintRepositorySectionTitle prints the repository section header
+func PrintRepositorySectionTitle() {
+	fmt.Println("\nREPOSITORY #############################################################################################################")
+	fmt.Println()
+}
+
+// DisplayRepositoryInformation prints where the repository is located and its commit history range
+func DisplayRepositoryInformation(information models.RepositoryInformation) {
+	fmt.Printf("Git directory              %s\n", information.GitDirectory)
+
+	// Remote URL - only show if there is one
+	if information.Remote != "" {
+		fmt.Printf("Remote                     %s\n", information.Remote)
+	}
+
+	// Show last modified only if there's no recent fetch, neither applies to historic analyses
+	if information.MostRecentFetch != "" {
+		fmt.Printf("Most recent fetch          %s\n", information.MostRecentFetch)
+	} else if information.LastModified != "" {
+		fmt.Printf("Last modified              %s\n", information.LastModified)
+	} else {
+		fmt.Printf("As of                      %s\n", information.AsOf.Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+
+	fmt.Printf("Refs                       %s\n", information.Refs)
+	if !information.Since.IsZero() {
+		fmt.Printf("Since                      %s\n", information.Since.Format("Mon, 02 Jan 2006"))
+	}
+	fmt.Printf("Most recent commit         %s\n", information.LastCommit)
+	fmt.Printf("First commit               %s\n", information.FirstCommit)
+
+	// The age is only known for repositories with commits
+	if !information.FirstDate.IsZero() {
+		fmt.Printf("Age                        %s\n", information.Age)
+	}
+}
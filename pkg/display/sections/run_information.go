@@ -2,23 +2,37 @@ package sections
 
 import (
 	"fmt"
-	"git-metrics/pkg/git"
+	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
 	"runtime"
 	"time"
 )
 
+// BuildRunInformation collects information about the system and the versions in use
+func BuildRunInformation(startTime time.Time, gitVersion string) models.RunInformation {
+	return models.RunInformation{
+		StartTime:         startTime,
+		CPUCores:          runtime.NumCPU(),
+		MemoryGigabytes:   utils.GetMemoryInGigabytes(),
+		OperatingSystem:   utils.GetOperatingSystemInformation(),
+		Chip:              utils.GetChipInformation(),
+		Terminal:          utils.GetTerminalInformation(),
+		GitMetricsVersion: utils.GetGitMetricsVersion(),
+		GitVersion:        gitVersion,
+	}
+}
+
 // DisplayRunInformation prints information about the system
-func DisplayRunInformation() {
+func DisplayRunInformation(information models.RunInformation) {
 	fmt.Println("RUN ####################################################################################################################")
 	fmt.Println()
-	fmt.Printf("Start time                 %s\n", time.Now().Format("Mon, 02 Jan 2006 15:04 MST"))
+	fmt.Printf("Start time                 %s\n", information.StartTime.Format("Mon, 02 Jan 2006 15:04 MST"))
 	fmt.Printf("Machine                    %d CPU cores with %d GB memory (%s on %s)\n",
-		runtime.NumCPU(),
-		utils.GetMemoryInGigabytes(),
-		utils.GetOperatingSystemInformation(),
-		utils.GetChipInformation())
-	fmt.Printf("Terminal                   %s\n", utils.GetTerminalInformation())
-	fmt.Printf("Git metrics version        %s\n", utils.GetGitMetricsVersion())
-	fmt.Printf("Git version                %s\n", git.GetGitVersion())
+		information.CPUCores,
+		information.MemoryGigabytes,
+		information.OperatingSystem,
+		information.Chip)
+	fmt.Printf("Terminal                   %s\n", information.Terminal)
+	fmt.Printf("Git metrics version        %s\n", information.GitMetricsVersion)
+	fmt.Printf("Git version                %s\n", information.GitVersion)
 }
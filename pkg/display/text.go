@@ -0,0 +1,128 @@
+package display
+
+import (
+	"fmt"
+	"git-metrics/pkg/display/sections"
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/progress"
+	"git-metrics/pkg/utils"
+	"os"
+	"strings"
+	"time"
+)
+
+// TextRenderer prints the human-readable report to standard output section by section,
+// showing a spinner while the data of a section is collected
+type TextRenderer struct {
+	startTime time.Time
+	interval  models.Interval
+}
+
+// StartSection prints the title of sections whose title is known before their data is
+// collected and starts the section spinner
+func (renderer *TextRenderer) StartSection(section models.Section) {
+	switch section {
+	case models.RunSection, models.FilesSection, models.FileExtensionGrowthSection, models.ConcernSection, models.BacktestSection, models.PolicySection:
+		// Printed at once when rendered
+		return
+	case models.RepositorySection:
+		sections.PrintRepositorySectionTitle()
+	case models.RangeSection:
+		sections.PrintRangeSectionTitle()
+	case models.GrowthSection:
+		// Growth rows are printed with progress while collecting, no spinner needed
+		sections.PrintGrowthSectionTitle(renderer.interval)
+		return
+	case models.FileExtensionsSection:
+		sections.PrintFileExtensionsSectionTitle()
+	case models.DirectoriesSection:
+		sections.PrintLargestDirectoriesSectionTitle()
+	}
+	progress.StartSectionSpinner()
+}
+
+// StartPeriod shows a progress row for the period while its growth is collected
+func (renderer *TextRenderer) StartPeriod(period models.Period, previous models.GrowthStatistics, beforePrevious models.GrowthStatistics) {
+	progress.StartProgress(period, previous, beforePrevious, renderer.startTime)
+}
+
+// FinishPeriod updates the progress row with the collected statistics of the period
+func (renderer *TextRenderer) FinishPeriod(statistics models.GrowthStatistics, previous models.GrowthStatistics) {
+	progress.SetCurrentProgressStatistics(statistics, previous)
+}
+
+// RenderSection stops the progress indicators and prints the section's data
+func (renderer *TextRenderer) RenderSection(section models.Section, report models.Report) {
+	progress.StopAll()
+
+	switch section {
+	case models.RunSection:
+		renderer.startTime = report.Run.StartTime
+		renderer.interval = report.Interval
+		progress.ConcernThresholds = report.ConcernThresholds
+		sections.DisplayRunInformation(report.Run)
+	case models.RepositorySection:
+		sections.DisplayRepositoryInformation(*report.Repository)
+	case models.RangeSection:
+		sections.DisplayRange(*report.Range)
+	case models.GrowthSection:
+		sections.DisplayUnifiedGrowth(report.Growth, report.Estimates, *report.Repository, report.Interval, report.ForecastModel, report.ConcernThresholds)
+	case models.ConcernSection:
+		sections.PrintConcernProjectionsSectionTitle()
+		sections.DisplayConcernProjections(report.ConcernProjections, report.ConcernThresholds, len(report.Estimates) > 0, *report.Repository, report.Interval)
+	case models.BacktestSection:
+		sections.PrintBacktestSectionTitle()
+		if report.Backtest != nil {
+			sections.DisplayBacktest(report.Backtest, report.Interval)
+		} else {
+			fmt.Printf("\nBacktest unavailable: Requires at least 4 complete %ss of commit history\n", report.Interval.Noun())
+		}
+	case models.FileExtensionsSection:
+		sections.PrintTopFileExtensions(report.LargestFileExtensions, report.Repository.TotalBlobs)
+	case models.FileExtensionGrowthSection:
+		if len(report.FileExtensionGrowth) > 0 {
+			sections.PrintFileExtensionGrowthSectionTitle()
+			sections.PrintFileExtensionGrowth(report.FileExtensionGrowth, report.Interval)
+		}
+	case models.DirectoriesSection:
+		sections.PrintLargestDirectories(report.LargestDirectories)
+	case models.FilesSection:
+		sections.PrintLargestFiles(report.LargestFiles)
+	case models.RateOfChangesSection:
+		if len(report.RateOfChanges.Periods) > 0 {
+			sections.PrintRateOfChangesSectionTitle()
+			sections.DisplayRateOfChanges(report.RateOfChanges, report.Interval)
+		}
+	case models.ContributorsSection:
+		if len(report.Authors.Periods) > 0 {
+			sections.DisplayContributorsWithMostCommits(report.Authors, report.Committers, report.Interval)
+		}
+	case models.PolicySection:
+		sections.PrintPolicySectionTitle()
+		sections.DisplayPolicyViolations(*report.Policy, report.Violations)
+	}
+}
+
+// Finish prints the run duration, memory footprint and peak memory of each section. For an
+// incomplete report it only notes on standard error that the analysis was interrupted.
+func (renderer *TextRenderer) Finish(report models.Report) error {
+	progress.StopAll()
+	if report.Incomplete {
+		fmt.Fprintf(os.Stderr, "\n\nInterrupted after %s. The report only contains the sections collected so far.\n",
+			utils.FormatDuration(report.Run.Duration))
+		return nil
+	}
+
+	fmt.Printf("\nFinished in %s with a memory footprint of %s.\n",
+		utils.FormatDuration(report.Run.Duration),
+		strings.TrimSpace(utils.FormatSize(int64(report.Run.MemoryFootprint))))
+
+	if len(report.Run.Phases) > 0 {
+		phases := make([]string, 0, len(report.Run.Phases))
+		for _, phase := range report.Run.Phases {
+			phases = append(phases, fmt.Sprintf("%s %s", phase.Section, strings.TrimSpace(utils.FormatSize(int64(phase.PeakMemory)))))
+		}
+		fmt.Printf("Peak memory per section: %s.\n", strings.Join(phases, ", "))
+	}
+	return nil
+}
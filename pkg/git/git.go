@@ -1,44 +1,89 @@
 package git
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"git-metrics/pkg/models"
 	"git-metrics/pkg/utils"
 )
 
-// CountedObjects keeps track of Git objects that have been counted
-var CountedObjects = make(map[string]bool)
+// minimumObjectNameLength is the length of the shortest full object name (SHA-1 in hexadecimal)
+const minimumObjectNameLength = 40
 
-// RunGitCommand runs a git command with the given arguments and returns its output
-func RunGitCommand(debug bool, args ...string) ([]byte, error) {
-	utils.DebugPrint(debug, "git %s", strings.Join(args, " "))
-	command := exec.Command("git", args...)
-	return command.Output()
+// maximumLineLength is the longest line read from git output, which bounds the length of paths
+const maximumLineLength = 1024 * 1024
+
+// Repository runs git commands inside a repository without changing the working directory of the process
+type Repository struct {
+	Path    string            // Directory of the repository's working tree or Git directory
+	Debug   bool              // Print executed git commands to stderr
+	Workers *utils.WorkerPool // Runs independent git commands concurrently, sequentially if nil
+	Runner  Runner            // Runs the git commands, ExecRunner if nil
+	AsOf    time.Time         // Ignore commits committed after this time unless zero
+	Refs    RefScope          // Refs whose history is analyzed
+}
+
+// CommitLimits returns the rev-list options that limit walks to the commits committed up to AsOf
+func (repository Repository) CommitLimits() []string {
+	if repository.AsOf.IsZero() {
+		return nil
+	}
+	return []string{"--before=" + repository.AsOf.Format(time.RFC3339)}
+}
+
+// run runs a git command with the given arguments inside the repository, reading its input from
+// stdin unless it is nil and writing its output to stdout
+func (repository Repository) run(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) error {
+	utils.DebugPrint(repository.Debug, "git %s", strings.Join(args, " "))
+	runner := repository.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	return runner.Run(ctx, repository.Path, stdin, stdout, args...)
+}
+
+// output runs a git command with the given arguments and input inside the repository and returns its output
+func (repository Repository) output(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	err := repository.run(ctx, stdin, &output, args...)
+	return output.Bytes(), err
+}
+
+// RunGitCommand runs a git command with the given arguments inside the repository and returns its output
+func (repository Repository) RunGitCommand(ctx context.Context, args ...string) ([]byte, error) {
+	return repository.output(ctx, nil, args...)
+}
+
+// RunGitCommandOnRevisions runs a git command that reads the given revisions from its standard
+// input and returns its output. It works for any number of revisions, unlike passing them as arguments.
+func (repository Repository) RunGitCommandOnRevisions(ctx context.Context, revisions []string, args ...string) ([]byte, error) {
+	return repository.output(ctx, revisionInput(revisions), append(args, "--stdin")...)
 }
 
 // GetGitVersion returns the installed git version
-func GetGitVersion() string {
-	if output, err := RunGitCommand(false, "version"); err == nil {
+func (repository Repository) GetGitVersion(ctx context.Context) string {
+	if output, err := repository.RunGitCommand(ctx, "version"); err == nil {
 		return strings.TrimPrefix(strings.TrimSpace(string(output)), "git version ")
 	}
 	return "Unknown"
 }
 
 // GetDefaultBranch detects and returns the default branch name (main, master, etc.)
-func GetDefaultBranch() (string, error) {
+func (repository Repository) GetDefaultBranch(ctx context.Context) (string, error) {
 	// First try to get the default branch from remote origin
-	cmd := exec.Command("git", "remote", "show", "origin")
-	output, err := cmd.Output()
+	output, err := repository.RunGitCommand(ctx, "remote", "show", "origin")
 	if err == nil {
 		lines := strings.Split(string(output), "\n")
 		for _, line := range lines {
@@ -51,15 +96,13 @@ func GetDefaultBranch() (string, error) {
 	// If that fails, check common default branch names
 	commonBranches := []string{"main", "master"}
 	for _, branch := range commonBranches {
-		cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
-		if cmd.Run() == nil {
+		if _, err := repository.RunGitCommand(ctx, "show-ref", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
 			return branch, nil
 		}
 	}
 
 	// If all else fails, try to get current branch
-	cmd = exec.Command("git", "branch", "--show-current")
-	output, err = cmd.Output()
+	output, err = repository.RunGitCommand(ctx, "branch", "--show-current")
 	if err == nil && len(output) > 0 {
 		return strings.TrimSpace(string(output)), nil
 	}
@@ -68,15 +111,29 @@ func GetDefaultBranch() (string, error) {
 }
 
 // GetBranchFiles returns a map of all files in the given branch
-func GetBranchFiles(branch string) (map[string]bool, error) {
-	cmd := exec.Command("git", "ls-tree", "-r", "--name-only", branch)
-	output, err := cmd.Output()
+func (repository Repository) GetBranchFiles(ctx context.Context, branch string) (map[string]bool, error) {
+	revision := branch
+	if !repository.AsOf.IsZero() {
+		// Use the files of the branch's latest commit up to AsOf
+		arguments := append(append([]string{"rev-list", "--max-count=1"}, repository.CommitLimits()...), branch)
+		output, err := repository.RunGitCommand(ctx, arguments...)
+		if err != nil {
+			return nil, err
+		}
+		revision = strings.TrimSpace(string(output))
+		if revision == "" {
+			return map[string]bool{}, nil
+		}
+	}
+
+	// Paths are separated by NUL, so git does not quote paths with special characters
+	output, err := repository.RunGitCommand(ctx, "ls-tree", "-r", "-z", "--name-only", revision)
 	if err != nil {
 		return nil, err
 	}
 
 	files := make(map[string]bool)
-	for _, file := range strings.Split(string(output), "\n") {
+	for _, file := range strings.Split(string(output), "\x00") {
 		if file != "" {
 			files[file] = true
 		}
@@ -85,15 +142,17 @@ func GetBranchFiles(branch string) (map[string]bool, error) {
 	return files, nil
 }
 
-// GetGitDirectory gets the path to the .git directory for a repository
-func GetGitDirectory(path string) (string, error) {
+// GetGitDirectory gets the path to the .git directory of the repository
+func (repository Repository) GetGitDirectory(ctx context.Context) (string, error) {
+	path := repository.Path
+
 	// Check if directory exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return "", fmt.Errorf("repository path does not exist: %s", path)
 	}
 
 	// Run git rev-parse to get git directory
-	gitDir, err := RunGitCommand(false, "-C", path, "rev-parse", "--git-dir")
+	gitDir, err := repository.RunGitCommand(ctx, "rev-parse", "--git-dir")
 	if err != nil {
 		return "", fmt.Errorf("not a git repository: %s", path)
 	}
@@ -131,137 +190,167 @@ func GetLastFetchTime(gitDir string) string {
 	return ""
 }
 
-// GetGrowthStats calculates repository growth statistics for a given year
-func GetGrowthStats(year int, previousGrowthStatistics models.GrowthStatistics, debug bool) (models.GrowthStatistics, error) {
-	utils.DebugPrint(debug, "Calculating stats for year %d", year)
-	currentStatistics := models.GrowthStatistics{Year: year}
-	startTime := time.Now()
+// objectInformationFormat is the cat-file format for an object's type, name, sizes and the rest of
+// the input line, which is the path for trees and blobs and the committer timestamp for commits
+const objectInformationFormat = "%(objecttype) %(objectname) %(objectsize) %(objectsize:disk) %(rest)"
+
+// ListObjects calls handle with the type, name, sizes and path or committer timestamp of every
+// object reachable from the given revisions as soon as cat-file reports it. Revisions prefixed
+// with ^ exclude everything reachable from them. Each object is listed once, directly after the
+// oldest commit that introduced it. The objects listed by rev-list are streamed into a single
+// long-lived cat-file process, so the listing is never held in memory.
+func (repository Repository) ListObjects(ctx context.Context, revisions []string, handle func(line string)) error {
+	revListOutput, revListWriter := io.Pipe()
+	catFileInput, catFileInputWriter := io.Pipe()
+	catFileOutput, catFileWriter := io.Pipe()
+
+	var revListError, catFileError error
+	var commands sync.WaitGroup
+	commands.Add(3)
+	go func() {
+		defer commands.Done()
+		arguments := append([]string{"rev-list", "--objects", "--in-commit-order", "--reverse", "--date-order", "--timestamp"}, repository.CommitLimits()...)
+		revListError = repository.run(ctx, revisionInput(revisions), revListWriter, append(arguments, "--stdin")...)
+		revListWriter.CloseWithError(revListError)
+	}()
+	go func() {
+		defer commands.Done()
+		catFileError = repository.run(ctx, catFileInput, catFileWriter, "cat-file", "--batch-check="+objectInformationFormat)
+		// Unblock writing the input if cat-file stopped early
+		catFileInput.CloseWithError(io.ErrClosedPipe)
+		catFileWriter.CloseWithError(catFileError)
+	}()
+	go func() {
+		defer commands.Done()
+		writer := bufio.NewWriter(catFileInputWriter)
+		scanner := bufio.NewScanner(revListOutput)
+		scanner.Buffer(nil, maximumLineLength)
+		for scanner.Scan() {
+			line := scanner.Text()
+			// Commits are listed as "<timestamp> <name>", cat-file expects the name first
+			if timestamp, name, found := strings.Cut(line, " "); found && len(timestamp) < minimumObjectNameLength {
+				line = name + " " + timestamp
+			}
+			if _, err := writer.WriteString(line + "\n"); err != nil {
+				break
+			}
+		}
+		writer.Flush()
+		catFileInputWriter.Close()
+		// Drain remaining output so rev-list can exit if cat-file stopped early
+		io.Copy(io.Discard, revListOutput)
+	}()
 
-	// Build shell command with before and after dates.
-	commandString := fmt.Sprintf("git rev-list --objects --all --before %d-01-01 --after %d-12-31 | git cat-file --batch-check='%%(objecttype) %%(objectname) %%(objectsize) %%(objectsize:disk) %%(rest)'", year+1, year-1)
-	command := exec.Command(ShellToUse(), "-c", commandString)
-	output, err := command.Output()
-	if err != nil {
-		return currentStatistics, err
+	scanner := bufio.NewScanner(catFileOutput)
+	scanner.Buffer(nil, maximumLineLength)
+	for scanner.Scan() {
+		handle(scanner.Text())
 	}
+	scanError := scanner.Err()
+	// Drain remaining output so cat-file can exit if a line could not be read
+	io.Copy(io.Discard, catFileOutput)
 
-	// Prepare a map to collect blob files (keyed by file path).
-	blobsMap := make(map[string]models.FileInformation)
-	var commitsDelta, treesDelta, blobsDelta int
-	var compressedDelta, uncompressedDelta int64
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		fields := strings.Fields(line)
-		if len(fields) < 4 {
-			continue
-		}
-		objectType := fields[0]
-		objectIdentifier := fields[1]
-		// Filter out objects already counted
-		if CountedObjects[objectIdentifier] {
-			continue
-		}
-		CountedObjects[objectIdentifier] = true
+	commands.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if revListError != nil {
+		return fmt.Errorf("git rev-list failed: %w", revListError)
+	}
+	if catFileError != nil {
+		return fmt.Errorf("git cat-file failed: %w", catFileError)
+	}
+	if scanError != nil {
+		return fmt.Errorf("reading git cat-file output failed: %w", scanError)
+	}
+	return nil
+}
 
-		uncompressedSize, err := strconv.ParseInt(fields[2], 10, 64)
-		if err != nil {
-			continue // Skip invalid size entries
-		}
-		compressedSize, err := strconv.ParseInt(fields[3], 10, 64)
-		if err != nil {
-			continue // Skip invalid size entries
+// ListNewestPaths returns the path of every blob reachable from the given revisions in the most
+// recent commit that contains it, keyed by binary object name. rev-list names each object by its
+// path in the first commit it walks, so this walk goes from the newest commits to the oldest.
+// Trees are filtered out by rev-list, as only blobs are reported by path.
+func (repository Repository) ListNewestPaths(ctx context.Context, revisions []string) (map[ObjectKey]string, error) {
+	revListOutput, revListWriter := io.Pipe()
+	var revListError error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		arguments := append([]string{"rev-list", "--objects", "--filter=object:type=blob"}, repository.CommitLimits()...)
+		revListError = repository.run(ctx, revisionInput(revisions), revListWriter, append(arguments, "--stdin")...)
+		revListWriter.CloseWithError(revListError)
+	}()
+
+	paths := make(map[ObjectKey]string)
+	// Most blobs share their path with other versions of the same file, so each path is kept once
+	uniquePaths := make(map[string]string)
+	scanner := bufio.NewScanner(revListOutput)
+	scanner.Buffer(nil, maximumLineLength)
+	for scanner.Scan() {
+		name, path, found := strings.Cut(scanner.Text(), " ")
+		if !found || path == "" {
+			continue // Commits have no path
+		}
+		key, ok := ParseObjectKey(name)
+		if !ok {
+			continue
 		}
-		compressedDelta += compressedSize
-		uncompressedDelta += uncompressedSize
-
-		switch objectType {
-		case "commit":
-			commitsDelta++
-		case "tree":
-			treesDelta++
-		case "blob":
-			blobsDelta++
-			// Collect blob if file path available (5th field onward)
-			if len(fields) >= 5 {
-				filePath := strings.Join(fields[4:], " ")
-				filePath = strings.TrimSpace(filePath)
-				if filePath != "" {
-					if existing, ok := blobsMap[filePath]; ok {
-						existing.Blobs++
-						existing.CompressedSize += compressedSize
-						existing.UncompressedSize += uncompressedSize
-						blobsMap[filePath] = existing
-					} else {
-						blobsMap[filePath] = models.FileInformation{
-							Path:             filePath,
-							Blobs:            1,
-							CompressedSize:   compressedSize,
-							UncompressedSize: uncompressedSize,
-							// LastChange remains zero as we do not parse it here
-						}
-					}
-				}
-			}
+		if unique, exists := uniquePaths[path]; exists {
+			path = unique
+		} else {
+			uniquePaths[path] = path
 		}
+		paths[key] = path
 	}
+	scanError := scanner.Err()
+	// Drain remaining output so rev-list can exit if a line could not be read
+	io.Copy(io.Discard, revListOutput)
 
-	currentStatistics.Commits = previousGrowthStatistics.Commits + commitsDelta
-	currentStatistics.Trees = previousGrowthStatistics.Trees + treesDelta
-	currentStatistics.Blobs = previousGrowthStatistics.Blobs + blobsDelta
-	currentStatistics.Compressed = previousGrowthStatistics.Compressed + compressedDelta
-	currentStatistics.Uncompressed = previousGrowthStatistics.Uncompressed + uncompressedDelta
-	currentStatistics.RunTime = time.Since(startTime)
-
-	// Convert blobsMap to slice.
-	var currentYearBlobs []models.FileInformation
-	for _, fileInfo := range blobsMap {
-		currentYearBlobs = append(currentYearBlobs, fileInfo)
-	}
-	// Merge with previousGrowthStatistics largest blobs.
-	mergedBlobsMap := make(map[string]models.FileInformation)
-	for _, blob := range previousGrowthStatistics.LargestFiles {
-		mergedBlobsMap[blob.Path] = blob
-	}
-	for _, blob := range currentYearBlobs {
-		if existing, ok := mergedBlobsMap[blob.Path]; ok {
-			existing.Blobs += blob.Blobs
-			existing.CompressedSize += blob.CompressedSize
-			existing.UncompressedSize += blob.UncompressedSize
-			mergedBlobsMap[blob.Path] = existing
-		} else {
-			mergedBlobsMap[blob.Path] = blob
-		}
+	<-done
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	var mergedBlobs []models.FileInformation
-	for _, blob := range mergedBlobsMap {
-		mergedBlobs = append(mergedBlobs, blob)
+	if revListError != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", revListError)
 	}
-	currentStatistics.LargestFiles = mergedBlobs
-
-	utils.DebugPrint(debug, "Finished calculating stats for year %d in %v", year, currentStatistics.RunTime)
-	return currentStatistics, nil
+	if scanError != nil {
+		return nil, fmt.Errorf("reading git rev-list output failed: %w", scanError)
+	}
+	return paths, nil
 }
 
-func ShellToUse() string {
-	if runtime.GOOS == "windows" {
-		return "bash"
-	}
-	return "sh"
+// objectInformation holds a single object reported by cat-file
+type objectInformation struct {
+	objectType       string
+	objectName       string
+	uncompressedSize int64
+	compressedSize   int64
+	rest             string // Path for trees and blobs, committer timestamp for commits
 }
 
-// GetContributors returns all commit authors and committers with dates from git history
-func GetContributors() ([]string, error) {
-	// Execute the git command to get all contributors with their commit dates
-	command := exec.Command("git", "log", "--all", "--format=%an|%cn|%cd", "--date=format:%Y")
-	output, err := command.Output()
+// parseObjectInformation parses a line in objectInformationFormat without splitting or trimming
+// the path, so paths containing spaces are kept as they are
+func parseObjectInformation(line string) (objectInformation, bool) {
+	objectType, line, _ := strings.Cut(line, " ")
+	objectName, line, _ := strings.Cut(line, " ")
+	uncompressedField, line, _ := strings.Cut(line, " ")
+	compressedField, rest, _ := strings.Cut(line, " ")
+
+	uncompressedSize, err := strconv.ParseInt(uncompressedField, 10, 64)
 	if err != nil {
-		return nil, err
+		return objectInformation{}, false
 	}
-
-	return strings.Split(string(output), "\n"), nil
+	compressedSize, err := strconv.ParseInt(compressedField, 10, 64)
+	if err != nil {
+		return objectInformation{}, false
+	}
+	return objectInformation{
+		objectType:       objectType,
+		objectName:       objectName,
+		uncompressedSize: uncompressedSize,
+		compressedSize:   compressedSize,
+		rest:             rest, // Paths may start or end with spaces
+	}, true
 }
 
 // contributorEntry stores the name and count for a contributor.
@@ -280,7 +369,7 @@ type commitInfo struct {
 
 // processContributors takes a map of contributor names to counts,
 // sorts them, and returns the top N contributors along with the total unique contributor count.
-func processContributors(contributors map[string]int, n int, year int) ([][3]string, int) {
+func processContributors(contributors map[string]int, n int, period models.Period) ([][3]string, int) {
 	var contributorList []contributorEntry
 	for name, count := range contributors {
 		contributorList = append(contributorList, contributorEntry{Name: name, Count: count})
@@ -302,139 +391,32 @@ func processContributors(contributors map[string]int, n int, year int) ([][3]str
 		topNContributors = append(topNContributors, [3]string{
 			contributor.Name,
 			strconv.Itoa(contributor.Count),
-			strconv.Itoa(year),
+			period.String(),
 		})
 	}
 	return topNContributors, len(contributors)
 }
 
-// GetTopCommitAuthors returns the top N commit authors and committers by number of commits, grouped by year
-func GetTopCommitAuthors(n int) (map[int][][3]string, map[int]int, map[int]int, map[int][][3]string, map[int]int, map[string]int, map[string]int, error) {
-	// Get all commit authors and committers with dates
-	lines, err := GetContributors()
-	if err != nil {
-		return nil, nil, nil, nil, nil, nil, nil, err
-	}
-	authorsByYear := make(map[int]map[string]int)
-	committersByYear := make(map[int]map[string]int)
-	totalCommitsByYear := make(map[int]int)
-
-	// Maps to track all unique authors and committers across all years
-	allTimeAuthors := make(map[string]int)
-	allTimeCommitters := make(map[string]int)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		parts := strings.Split(line, "|")
-		if len(parts) != 3 {
-			continue
-		}
-
-		author := parts[0]
-		committer := parts[1]
-		yearStr := parts[2]
-
-		year, err := strconv.Atoi(yearStr)
+// GetRateOfChanges calculates commit rate statistics by period of the interval for the commits
+// reachable from the given tips and committed since the given time unless it is zero. Without
+// tips, it uses the current branch and returns its name.
+func (repository Repository) GetRateOfChanges(ctx context.Context, tips []string, interval models.Interval, since time.Time) (map[models.Period]models.RateStatistics, string, error) {
+	limits := repository.CommitLimits()
+	if !since.IsZero() {
+		limits = append(limits, "--since="+since.Format(time.RFC3339))
+	}
+	if len(tips) > 0 {
+		arguments := append([]string{"log", "--format=%ct|%P|%an", "--reverse"}, limits...)
+		output, err := repository.RunGitCommandOnRevisions(ctx, tips, arguments...)
 		if err != nil {
-			continue
-		}
-
-		if _, exists := authorsByYear[year]; !exists {
-			authorsByYear[year] = make(map[string]int)
-		}
-		if _, exists := committersByYear[year]; !exists {
-			committersByYear[year] = make(map[string]int)
-		}
-
-		authorsByYear[year][author]++
-		committersByYear[year][committer]++
-		totalCommitsByYear[year]++
-
-		// Track all unique authors and committers across all years
-		allTimeAuthors[author]++
-		allTimeCommitters[committer]++
-	}
-
-	// Convert to result format: map[year] -> sorted authors/committers
-	authorResult := make(map[int][][3]string)
-	committerResult := make(map[int][][3]string)
-	totalAuthorsByYear := make(map[int]int)
-	totalCommittersByYear := make(map[int]int)
-
-	// Process authors
-	for year, authors := range authorsByYear {
-		topAuthors, total := processContributors(authors, n, year)
-		authorResult[year] = topAuthors
-		totalAuthorsByYear[year] = total
-	}
-
-	// Process committers
-	for year, committers := range committersByYear {
-		topCommitters, total := processContributors(committers, n, year)
-		committerResult[year] = topCommitters
-		totalCommittersByYear[year] = total
-	}
-
-	return authorResult, totalAuthorsByYear, totalCommitsByYear, committerResult, totalCommittersByYear, allTimeAuthors, allTimeCommitters, nil
-}
-
-// GetCumulativeUniqueAuthorsByYear returns a map of year -> cumulative unique author count
-// along with the final total unique authors across all years.
-func GetCumulativeUniqueAuthorsByYear() (map[int]int, int, error) {
-	lines, err := GetContributors()
-	if err != nil {
-		return nil, 0, err
-	}
-	// authors seen per year (for union later) and global set
-	authorsPerYear := make(map[int]map[string]struct{})
-	yearsSet := make(map[int]struct{})
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, "|")
-		if len(parts) != 3 {
-			continue
-		}
-		author := parts[0]
-		yearStr := parts[2]
-		year, convErr := strconv.Atoi(yearStr)
-		if convErr != nil {
-			continue
+			return nil, "", fmt.Errorf("failed to get commit log: %v", err)
 		}
-		if _, ok := authorsPerYear[year]; !ok {
-			authorsPerYear[year] = make(map[string]struct{})
-		}
-		authorsPerYear[year][author] = struct{}{}
-		yearsSet[year] = struct{}{}
-	}
-	// Sort years
-	var years []int
-	for y := range yearsSet {
-		years = append(years, y)
-	}
-	sort.Ints(years)
-	cumulativeCounts := make(map[int]int)
-	cumulativeSet := make(map[string]struct{})
-	for _, y := range years {
-		for author := range authorsPerYear[y] {
-			cumulativeSet[author] = struct{}{}
-		}
-		cumulativeCounts[y] = len(cumulativeSet)
+		rateStats, err := calculateRateStatistics(string(output), interval)
+		return rateStats, "", err
 	}
-	return cumulativeCounts, len(cumulativeSet), nil
-}
 
-// GetRateOfChanges calculates commit rate statistics for the current branch by year
-func GetRateOfChanges() (map[int]models.RateStatistics, string, error) {
 	// Get current branch name instead of remote default branch
-	cmd := exec.Command("git", "branch", "--show-current")
-	branchOutput, err := cmd.Output()
+	branchOutput, err := repository.RunGitCommand(ctx, "branch", "--show-current")
 	if err != nil {
 		return nil, "", fmt.Errorf("could not determine current branch: %v", err)
 	}
@@ -444,25 +426,25 @@ func GetRateOfChanges() (map[int]models.RateStatistics, string, error) {
 	}
 
 	// Get all commits from current branch with timestamps, merge info, and authors
-	command := exec.Command("git", "log", currentBranch, "--format=%ct|%P|%an", "--reverse")
-	output, err := command.Output()
+	arguments := append([]string{"log", currentBranch, "--format=%ct|%P|%an", "--reverse"}, limits...)
+	output, err := repository.RunGitCommand(ctx, arguments...)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get commit log: %v", err)
 	}
 
-	rateStats, err := calculateRateStatistics(string(output))
+	rateStats, err := calculateRateStatistics(string(output), interval)
 	return rateStats, currentBranch, err
 }
 
-// calculateRateStatistics processes git log output and calculates rate statistics
-func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics, error) {
+// calculateRateStatistics processes git log output and calculates rate statistics for each period of the interval
+func calculateRateStatistics(gitLogOutput string, interval models.Interval) (map[models.Period]models.RateStatistics, error) {
 	lines := strings.Split(strings.TrimSpace(gitLogOutput), "\n")
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("no commits found")
 	}
 
-	// Parse commits by year
-	commitsByYear := make(map[int][]commitInfo)
+	// Parse commits by period
+	commitsByPeriod := make(map[models.Period][]commitInfo)
 	var totalCommits int
 
 	for _, line := range lines {
@@ -470,7 +452,8 @@ func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics
 			continue
 		}
 
-		parts := strings.Split(line, "|")
+		// Author names may contain the separator, so they are the last field
+		parts := strings.SplitN(line, "|", 3)
 		if len(parts) != 3 {
 			continue
 		}
@@ -494,8 +477,8 @@ func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics
 		weekday := commitTime.Weekday()
 		isWorkday := weekday >= time.Monday && weekday <= time.Friday
 
-		year := commitTime.Year()
-		commitsByYear[year] = append(commitsByYear[year], commitInfo{
+		period := interval.PeriodOf(commitTime)
+		commitsByPeriod[period] = append(commitsByPeriod[period], commitInfo{
 			timestamp: commitTime,
 			isMerge:   isMerge,
 			isWorkday: isWorkday,
@@ -504,12 +487,12 @@ func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics
 		totalCommits++
 	}
 
-	// Calculate statistics for each year
-	ratesByYear := make(map[int]models.RateStatistics)
+	// Calculate statistics for each period
+	ratesByPeriod := make(map[models.Period]models.RateStatistics)
 
-	for year, commits := range commitsByYear {
+	for period, commits := range commitsByPeriod {
 		stats := models.RateStatistics{
-			Year:              year,
+			Period:            period,
 			TotalCommits:      len(commits),
 			PercentageOfTotal: float64(len(commits)) / float64(totalCommits) * 100,
 		}
@@ -561,11 +544,8 @@ func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics
 		}
 
 		// Calculate average commits per day
-		daysInYear := 365
-		if isLeapYear(year) {
-			daysInYear = 366
-		}
-		stats.AverageCommitsPerDay = float64(stats.TotalCommits) / float64(daysInYear)
+		daysInPeriod := period.End().Sub(period.Start()).Hours() / 24
+		stats.AverageCommitsPerDay = float64(stats.TotalCommits) / daysInPeriod
 
 		// Find busiest day and calculate percentiles
 		var dailyCounts []int
@@ -615,10 +595,10 @@ func calculateRateStatistics(gitLogOutput string) (map[int]models.RateStatistics
 			stats.MinutelyPeakP100 = minutelyCounts[len(minutelyCounts)-1] // Maximum value
 		}
 
-		ratesByYear[year] = stats
+		ratesByPeriod[period] = stats
 	}
 
-	return ratesByYear, nil
+	return ratesByPeriod, nil
 }
 
 // calculatePercentile calculates the nth percentile of a sorted slice
@@ -641,8 +621,3 @@ func calculatePercentile(sortedData []int, percentile int) int {
 	weight := index - float64(lower)
 	return int(float64(sortedData[lower])*(1-weight) + float64(sortedData[upper])*weight)
 }
-
-// isLeapYear checks if a given year is a leap year
-func isLeapYear(year int) bool {
-	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
-}
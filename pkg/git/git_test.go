@@ -1,14 +1,20 @@
 package git
 
 import (
+	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"git-metrics/pkg/models"
 )
 
 func TestGetGitVersion(t *testing.T) {
-	version := GetGitVersion()
+	version := Repository{}.GetGitVersion(context.Background())
 
 	// We can't predict the exact version, but we can check that it's not empty
 	// and follows a typical format like "2.35.1" or similar
@@ -91,7 +97,7 @@ func TestGetGitDirectory(t *testing.T) {
 				defer tt.cleanupFunc(path)
 			}
 
-			gitDir, err := GetGitDirectory(tt.path)
+			gitDir, err := Repository{Path: tt.path}.GetGitDirectory(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetGitDirectory() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -106,7 +112,276 @@ func TestGetGitDirectory(t *testing.T) {
 	}
 }
 
-// Mock for testing
-func mockRunGitCommand(_ bool, _ ...string) ([]byte, error) {
-	return []byte("git version 2.35.1"), nil
+// commitFile writes a file and commits it with the given committer and author date
+func commitFile(t *testing.T, repositoryPath, path, content, date string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(repositoryPath, path)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repositoryPath, path), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "--all"}, {"commit", "--quiet", "-m", "Change " + path}} {
+		command := exec.Command("git", args...)
+		command.Dir = repositoryPath
+		command.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test user", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test user", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+// walkHistory updates history with everything reachable from the current refs
+func walkHistory(t *testing.T, repository Repository, history *History, progress func(month models.Period, running models.GrowthStatistics)) {
+	t.Helper()
+	tips, err := repository.GetRefTips(context.Background())
+	if err != nil {
+		t.Fatalf("GetRefTips() error = %v", err)
+	}
+	if err := repository.UpdateHistory(context.Background(), history, tips, AllHistoryParts, progress); err != nil {
+		t.Fatalf("UpdateHistory() error = %v", err)
+	}
+}
+
+func TestUpdateHistory(t *testing.T) {
+	repositoryPath := t.TempDir()
+	if output, err := exec.Command("git", "init", "--quiet", repositoryPath).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	repository := Repository{Path: repositoryPath}
+	commitFile(t, repositoryPath, "README.md", "first", "2021-06-01T12:00:00Z")
+	commitFile(t, repositoryPath, "docs/guide.md", "second", "2023-06-01T12:00:00Z")
+
+	year := func(year int) models.Period { return models.Period{Interval: models.YearInterval, Index: year} }
+	var progressMonths []string
+	history := NewHistory()
+	walkHistory(t, repository, history, func(month models.Period, _ models.GrowthStatistics) {
+		progressMonths = append(progressMonths, month.String())
+	})
+	statistics := history.GrowthStatistics(year(2021), year(2024))
+
+	// Objects are attributed to the period of the commit that introduced them and accumulated
+	expected := map[int][3]int{ // commits, trees, blobs
+		2021: {1, 1, 1},
+		2022: {1, 1, 1},
+		2023: {2, 3, 2}, // the second commit adds a root and a docs tree
+		2024: {2, 3, 2},
+	}
+	for expectedYear, counts := range expected {
+		actual := statistics[year(expectedYear)]
+		if actual.Commits != counts[0] || actual.Trees != counts[1] || actual.Blobs != counts[2] {
+			t.Errorf("year %d: expected commits, trees, blobs %v, got %d, %d, %d", expectedYear, counts, actual.Commits, actual.Trees, actual.Blobs)
+		}
+	}
+	if files := statistics[year(2023)].LargestFiles; len(files) != 2 {
+		t.Errorf("expected 2 files in 2023, got %+v", files)
+	}
+	for _, file := range statistics[year(2023)].LargestFiles {
+		if file.LargestBlob != file.UncompressedSize {
+			t.Errorf("expected the only blob of %s to be its largest, got %+v", file.Path, file)
+		}
+	}
+	if !slices.Equal(progressMonths, []string{"2021-06", "2023-06"}) {
+		t.Errorf("expected progress when the walk reached 2021-06 and 2023-06, got %v", progressMonths)
+	}
+
+	// Shorter periods split the same history, earlier commits count towards the first period
+	quarters := history.GrowthStatistics(models.QuarterInterval.PeriodOf(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)), models.QuarterInterval.PeriodOf(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)))
+	if len(quarters) != 2 {
+		t.Fatalf("expected statistics for 2 quarters, got %v", quarters)
+	}
+	for period, statistics := range quarters {
+		if statistics.Period != period || statistics.Commits != 2 {
+			t.Errorf("expected 2 commits up to %v, got %+v", period, statistics)
+		}
+	}
+
+	// Updating walks only the new commit and ends up with the same result as a complete walk
+	commitFile(t, repositoryPath, "README.md", "third", "2024-06-01T12:00:00Z")
+	progressMonths = nil
+	walkHistory(t, repository, history, func(month models.Period, running models.GrowthStatistics) {
+		progressMonths = append(progressMonths, month.String())
+		if running.Commits != 2 {
+			t.Errorf("expected the update to continue with 2 commits, got %d", running.Commits)
+		}
+	})
+	complete := NewHistory()
+	walkHistory(t, repository, complete, nil)
+
+	updated, expectedStatistics := history.GrowthStatistics(year(2021), year(2024))[year(2024)], complete.GrowthStatistics(year(2021), year(2024))[year(2024)]
+	if updated.Commits != 3 || updated.Commits != expectedStatistics.Commits || updated.Trees != expectedStatistics.Trees ||
+		updated.Blobs != expectedStatistics.Blobs || updated.Compressed != expectedStatistics.Compressed {
+		t.Errorf("expected updated totals %+v, got %+v", expectedStatistics, updated)
+	}
+	if !slices.Equal(progressMonths, []string{"2024-06"}) {
+		t.Errorf("expected progress only for 2024-06, got %v", progressMonths)
+	}
+	if _, _, totalCommits, _, _, allTimeAuthors, _ := history.TopCommitAuthors(3, models.YearInterval); totalCommits[year(2024)] != 1 || allTimeAuthors["Test user"] != 3 {
+		t.Errorf("expected 1 commit in 2024 and 3 commits of Test user, got %v and %v", totalCommits, allTimeAuthors)
+	}
+
+	// Splitting separates the commits before a month from the later ones
+	before, after := history.Split(models.MonthInterval.PeriodOf(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)))
+	if totals := before.GrowthStatistics(year(2024), year(2024))[year(2024)]; totals.Commits != 1 || totals.Blobs != 1 {
+		t.Errorf("expected 1 commit and blob before 2023-06, got %+v", totals)
+	}
+	if _, _, _, _, _, allTimeAuthors, _ := after.TopCommitAuthors(3, models.YearInterval); allTimeAuthors["Test user"] != 2 {
+		t.Errorf("expected 2 commits of Test user from 2023-06 on, got %v", allTimeAuthors)
+	}
+
+	// Each part is walked on its own, and only the parts not yet walked from the tips are added later
+	partial := NewHistory()
+	if err := repository.UpdateHistory(context.Background(), partial, complete.ObjectTips, HistoryParts{Contributors: true}, nil); err != nil {
+		t.Fatalf("UpdateHistory() error = %v", err)
+	}
+	if len(partial.Objects) != 0 || partial.ObjectTips != nil || partial.Walked(complete.ObjectTips, AllHistoryParts) {
+		t.Errorf("expected only the contributors to be walked, got %+v", partial)
+	}
+	if err := repository.UpdateHistory(context.Background(), partial, complete.ObjectTips, AllHistoryParts, nil); err != nil {
+		t.Fatalf("UpdateHistory() error = %v", err)
+	}
+	if totals := partial.GrowthStatistics(year(2024), year(2024))[year(2024)]; totals.Commits != 3 || totals.Blobs != expectedStatistics.Blobs {
+		t.Errorf("expected the totals %+v, got %+v", expectedStatistics, totals)
+	}
+	if _, _, _, _, _, allTimeAuthors, _ := partial.TopCommitAuthors(3, models.YearInterval); allTimeAuthors["Test user"] != 3 {
+		t.Errorf("expected the contributors to be counted once, got %v", allTimeAuthors)
+	}
+
+	// A history that is no longer reachable from the refs has to be walked again
+	contained, err := repository.ContainsHistory(context.Background(), complete.ObjectTips, history)
+	if err != nil || !contained {
+		t.Errorf("expected refs to contain the history, got %v, %v", contained, err)
+	}
+	resetCommand := exec.Command("git", "reset", "--quiet", "--hard", "HEAD~1")
+	resetCommand.Dir = repositoryPath
+	if output, err := resetCommand.CombinedOutput(); err != nil {
+		t.Fatalf("git reset failed: %v\n%s", err, output)
+	}
+	tips, _ := repository.GetRefTips(context.Background())
+	if contained, err := repository.ContainsHistory(context.Background(), tips, history); err != nil || contained {
+		t.Errorf("expected refs not to contain the history after a reset, got %v, %v", contained, err)
+	}
+}
+
+func TestParseObjectInformation(t *testing.T) {
+	tests := []struct {
+		line     string
+		ok       bool
+		expected objectInformation
+	}{
+		{
+			line:     "blob 3b18e512dba79e4c8300dd08aeb37f8e728b8dad 12 22 docs/release  notes.md",
+			ok:       true,
+			expected: objectInformation{objectType: "blob", objectName: "3b18e512dba79e4c8300dd08aeb37f8e728b8dad", uncompressedSize: 12, compressedSize: 22, rest: "docs/release  notes.md"},
+		},
+		{
+			line:     "commit 8f94139338f9404f26296befa88755fc2598c289 230 154 1717243200",
+			ok:       true,
+			expected: objectInformation{objectType: "commit", objectName: "8f94139338f9404f26296befa88755fc2598c289", uncompressedSize: 230, compressedSize: 154, rest: "1717243200"},
+		},
+		{
+			line:     "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904 0 9 ",
+			ok:       true,
+			expected: objectInformation{objectType: "tree", objectName: "4b825dc642cb6eb9a060e54bf8d69288fbee4904", compressedSize: 9},
+		},
+		{line: "4b825dc642cb6eb9a060e54bf8d69288fbee4904 missing", ok: false},
+	}
+
+	for _, test := range tests {
+		actual, ok := parseObjectInformation(test.line)
+		if ok != test.ok || actual != test.expected {
+			t.Errorf("parseObjectInformation(%q) = %+v, %v, expected %+v, %v", test.line, actual, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestGetRateOfChangesWithOddAuthorNames(t *testing.T) {
+	repository := Repository{Runner: &ReplayingRunner{Recordings: []Recording{
+		{Args: []string{"branch", "--show-current"}, Stdout: "main\n"},
+		{Args: []string{"log", "main", "--format=%ct|%P|%an", "--reverse"}, Stdout: "" +
+			"1717243200||Jane | Doe\n" + // Saturday
+			"1717329600|1111111111111111111111111111111111111111 2222222222222222222222222222222222222222|Zoë Ünal\n" +
+			"1717416000|3333333333333333333333333333333333333333|Seán O'Brien, Jr.\n"},
+	}}}
+
+	ratesByMonth, branch, err := repository.GetRateOfChanges(context.Background(), nil, models.MonthInterval, time.Time{})
+	if err != nil || branch != "main" {
+		t.Fatalf("GetRateOfChanges() = %v, %q, %v", ratesByMonth, branch, err)
+	}
+	rates := ratesByMonth[models.Period{Interval: models.MonthInterval, Index: 2024*12 + 5}]
+	if rates.TotalCommits != 3 || rates.MergeCommits != 1 || rates.ActiveAuthors != 3 || rates.AverageCommitsPerDay != 0.1 {
+		t.Errorf("expected 3 commits of 3 authors with 1 merge in June 2024, got %+v", rates)
+	}
+}
+
+func TestGetBranchFilesWithSpecialPaths(t *testing.T) {
+	repository := Repository{Runner: &ReplayingRunner{Recordings: []Recording{
+		{Args: []string{"ls-tree", "-r", "-z", "--name-only", "main"}, Stdout: "docs/release notes.md\x00docs/Übersicht.md\x00\"quoted\".txt\x00line\nbreak.txt\x00"},
+	}}}
+
+	files, err := repository.GetBranchFiles(context.Background(), "main")
+	if err != nil {
+		t.Fatalf("GetBranchFiles() error = %v", err)
+	}
+	for _, path := range []string{"docs/release notes.md", "docs/Übersicht.md", "\"quoted\".txt", "line\nbreak.txt"} {
+		if !files[path] {
+			t.Errorf("expected %q in %v", path, files)
+		}
+	}
+	if len(files) != 4 {
+		t.Errorf("expected 4 files, got %v", files)
+	}
+}
+
+func TestUpdateHistoryWithOddNamesAndPaths(t *testing.T) {
+	commit := "1111111111111111111111111111111111111111"
+	repository := Repository{Runner: &ReplayingRunner{Recordings: []Recording{
+		{
+			Args:  []string{"cat-file", "--batch-check=" + objectInformationFormat},
+			Stdin: commit + " 1717243200\n2222222222222222222222222222222222222222 \n3333333333333333333333333333333333333333 docs/release notes.md\n4444444444444444444444444444444444444444 trailing space.md \n5555555555555555555555555555555555555555 old name.md\n",
+			Stdout: "commit " + commit + " 230 154 1717243200\n" +
+				"tree 2222222222222222222222222222222222222222 100 90 \n" +
+				"blob 3333333333333333333333333333333333333333 12 22 docs/release notes.md\n" +
+				"blob 4444444444444444444444444444444444444444 5 15 trailing space.md \n" +
+				"blob 5555555555555555555555555555555555555555 7 17 old name.md\n",
+		},
+		{
+			Args: []string{"rev-list", "--objects", "--in-commit-order", "--reverse", "--date-order", "--timestamp", "--stdin"},
+			Stdout: "1717243200 " + commit + "\n" +
+				"2222222222222222222222222222222222222222 \n" +
+				"3333333333333333333333333333333333333333 docs/release notes.md\n" +
+				"4444444444444444444444444444444444444444 trailing space.md \n" +
+				"5555555555555555555555555555555555555555 old name.md\n",
+		},
+		{
+			// The newest commit renamed the last file
+			Args: []string{"rev-list", "--objects", "--filter=object:type=blob", "--stdin"},
+			Stdout: "6666666666666666666666666666666666666666\n" +
+				"3333333333333333333333333333333333333333 docs/release notes.md\n" +
+				"4444444444444444444444444444444444444444 trailing space.md \n" +
+				"5555555555555555555555555555555555555555 new name.md\n" +
+				commit + "\n",
+		},
+		{Args: []string{"log", "--format=%an%x00%cn%x00%cd", "--date=format:%Y-%m", "--stdin"}, Stdout: "Jane | Doe\x00Zoë Ünal\x002024-06\n"},
+	}}}
+
+	history := NewHistory()
+	if err := repository.UpdateHistory(context.Background(), history, []string{commit}, AllHistoryParts, nil); err != nil {
+		t.Fatalf("UpdateHistory() error = %v", err)
+	}
+
+	june := models.Period{Interval: models.MonthInterval, Index: 2024*12 + 5}
+	files := history.Objects[june].Files
+	if files["docs/release notes.md"].CompressedSize != 22 || files["trailing space.md "].CompressedSize != 15 {
+		t.Errorf("expected the paths to be kept as they are, got %+v", files)
+	}
+	if _, exists := files["old name.md"]; exists || files["new name.md"].CompressedSize != 17 {
+		t.Errorf("expected the renamed file under its newest path, got %+v", files)
+	}
+	if contributors := history.Contributors[june]; contributors == nil || contributors.Authors["Jane | Doe"] != 1 || contributors.Committers["Zoë Ünal"] != 1 {
+		t.Errorf("expected the names to be kept as they are, got %+v", contributors)
+	}
 }
@@ -0,0 +1,475 @@
+package git
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// History holds what the commits and objects reachable from a set of ref tips introduced in each
+// month. It can be extended with the commits added since, so history that was walked once does
+// not have to be walked again.
+type History struct {
+	ObjectTips      []string                               // Sorted names of the objects HEAD and all refs pointed to when the objects were walked
+	ContributorTips []string                               // Sorted names of the objects HEAD and all refs pointed to when the contributors were counted
+	Objects         map[models.Period]*MonthlyObjects      // Keyed by the month of the commit that introduced the objects
+	Contributors    map[models.Period]*MonthlyContributors // Keyed by commit month
+
+	// Names of all walked objects or nil if they are not tracked. Updating a history that was
+	// walked before counts objects again that reappear in the added commits, unless they are tracked.
+	ObjectNames *ObjectSet
+}
+
+// HistoryParts selects the parts of a History that UpdateHistory walks. Each part has its own walk,
+// so sections that need only one of them do not pay for the other.
+type HistoryParts struct {
+	Objects      bool // Objects introduced in each month, listed by rev-list and cat-file
+	Contributors bool // Commits of each author and committer in each month, read from git log
+}
+
+// AllHistoryParts selects the objects as well as the contributors
+var AllHistoryParts = HistoryParts{Objects: true, Contributors: true}
+
+// MonthlyObjects holds the objects introduced by the commits of a single month
+type MonthlyObjects struct {
+	Commits, Trees, Blobs    int
+	Compressed, Uncompressed int64
+	Files                    map[string]models.FileInformation
+}
+
+// MonthlyContributors holds the number of commits of each author and committer within a single month
+type MonthlyContributors struct {
+	Commits    int
+	Authors    map[string]int
+	Committers map[string]int
+}
+
+// NewHistory returns a history that does not contain any commits yet
+func NewHistory() *History {
+	return &History{
+		Objects:      make(map[models.Period]*MonthlyObjects),
+		Contributors: make(map[models.Period]*MonthlyContributors),
+	}
+}
+
+// Walked reports whether the selected parts of the history were walked from tips
+func (history *History) Walked(tips []string, parts HistoryParts) bool {
+	return (!parts.Objects || slices.Equal(history.ObjectTips, tips)) && (!parts.Contributors || slices.Equal(history.ContributorTips, tips))
+}
+
+// objectsOf returns the objects introduced in month, adding an empty entry if necessary
+func (history *History) objectsOf(month models.Period) *MonthlyObjects {
+	objects, exists := history.Objects[month]
+	if !exists {
+		objects = &MonthlyObjects{Files: make(map[string]models.FileInformation)}
+		history.Objects[month] = objects
+	}
+	return objects
+}
+
+// contributorsOf returns the contributors of month, adding an empty entry if necessary
+func (history *History) contributorsOf(month models.Period) *MonthlyContributors {
+	contributors, exists := history.Contributors[month]
+	if !exists {
+		contributors = &MonthlyContributors{Authors: make(map[string]int), Committers: make(map[string]int)}
+		history.Contributors[month] = contributors
+	}
+	return contributors
+}
+
+// revisionInput returns the revisions as input for a git command reading them with --stdin
+func revisionInput(revisions []string) io.Reader {
+	return strings.NewReader(strings.Join(revisions, "\n") + "\n")
+}
+
+// excluding returns tips followed by the excluded revisions prefixed with ^
+func excluding(tips, excluded []string) []string {
+	revisions := append([]string{}, tips...)
+	for _, revision := range excluded {
+		revisions = append(revisions, "^"+revision)
+	}
+	return revisions
+}
+
+// GetRefTips returns the sorted names of the objects the refs within the repository's scope point to
+func (repository Repository) GetRefTips(ctx context.Context) ([]string, error) {
+	output, err := repository.RunGitCommand(ctx, "show-ref", "--head")
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		tip, ref, found := strings.Cut(line, " ")
+		if found && repository.Refs.Matches(ref) {
+			unique[tip] = true
+		}
+	}
+	tips := make([]string, 0, len(unique))
+	for tip := range unique {
+		tips = append(tips, tip)
+	}
+	sort.Strings(tips)
+	return tips, nil
+}
+
+// ContainsHistory reports whether everything reachable from the history's tips is still reachable
+// from tips. Otherwise the history counts objects that were removed, for example by a force push,
+// and has to be walked again from scratch.
+func (repository Repository) ContainsHistory(ctx context.Context, tips []string, history *History) (bool, error) {
+	historyTips := append(append([]string{}, history.ObjectTips...), history.ContributorTips...)
+	output, err := repository.output(ctx, revisionInput(excluding(historyTips, tips)), "rev-list", "--objects", "--stdin")
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(output))) == 0, nil
+}
+
+// UpdateHistory adds the commits and objects reachable from tips but not from the tips each
+// selected part of the history was walked from to history and sets those tips. progress is called
+// with the totals of all objects walked so far whenever the object walk moves on to a later month
+// and may be nil.
+func (repository Repository) UpdateHistory(ctx context.Context, history *History, tips []string, parts HistoryParts, progress func(month models.Period, running models.GrowthStatistics)) error {
+	walkObjects := parts.Objects && !slices.Equal(tips, history.ObjectTips)
+	walkContributors := parts.Contributors && !slices.Equal(tips, history.ContributorTips)
+	if !walkObjects && !walkContributors {
+		return nil
+	}
+	startTime := time.Now()
+
+	// Blobs are named by their newest path, which the blobs walked before lose if the added commits
+	// move or copy them. rev-list does not list them again, so the objects are walked from scratch.
+	if walkObjects && history.ObjectNames != nil && len(history.ObjectTips) > 0 {
+		added, err := repository.addsWalkedBlobs(ctx, history, excluding(tips, history.ObjectTips))
+		if err != nil {
+			return err
+		}
+		if added {
+			utils.DebugPrint(repository.Debug, "Walking all objects again: the added commits add blobs that were walked before")
+			history.Objects = make(map[models.Period]*MonthlyObjects)
+			history.ObjectNames = NewObjectSet()
+			history.ObjectTips = nil
+		}
+	}
+
+	// Contributors are counted in a separate walk that can run alongside the object walk
+	var contributorsError error
+	waitForContributors := func() {}
+	if walkContributors {
+		revisions := excluding(tips, history.ContributorTips)
+		waitForContributors = repository.Workers.Go(func() {
+			contributorsError = repository.updateContributors(ctx, history, revisions)
+		})
+	}
+	var objectsError error
+	if walkObjects {
+		objectsError = repository.updateObjects(ctx, history, excluding(tips, history.ObjectTips), progress)
+	}
+	waitForContributors()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if objectsError != nil {
+		return objectsError
+	}
+	if contributorsError != nil {
+		return contributorsError
+	}
+
+	if walkObjects {
+		if history.ObjectNames != nil {
+			history.ObjectNames.Merge()
+		}
+		history.ObjectTips = tips
+	}
+	if walkContributors {
+		history.ContributorTips = tips
+	}
+	utils.DebugPrint(repository.Debug, "Finished walking history in %v", time.Since(startTime))
+	return nil
+}
+
+// updateObjects adds the objects reachable from revisions to history. Each object is attributed to
+// the month of the first commit that introduced it, and blobs are named by their path in the most
+// recent commit that contains them.
+func (repository Repository) updateObjects(ctx context.Context, history *History, revisions []string, progress func(month models.Period, running models.GrowthStatistics)) error {
+	// Continue with the totals and latest month of the history walked before
+	var running models.GrowthStatistics
+	currentMonth := models.Period{Interval: models.MonthInterval}
+	for month, objects := range history.Objects {
+		running.Commits += objects.Commits
+		running.Trees += objects.Trees
+		running.Blobs += objects.Blobs
+		running.Compressed += objects.Compressed
+		running.Uncompressed += objects.Uncompressed
+		if month.Index > currentMonth.Index {
+			currentMonth = month
+		}
+	}
+
+	// Objects are listed with their path in the commit that introduced them, but files are named
+	// by their newest path, so a renamed or moved file is not reported under a path that is gone
+	newestPaths, err := repository.ListNewestPaths(ctx, revisions)
+	if err != nil {
+		return err
+	}
+
+	var current *MonthlyObjects
+	return repository.ListObjects(ctx, revisions, func(line string) {
+		object, ok := parseObjectInformation(line)
+		if !ok {
+			return // Skip missing objects and invalid size entries
+		}
+		if history.ObjectNames != nil {
+			// rev-list only excludes the objects of the excluded tips' trees, not of their whole history
+			if history.ObjectNames.Contains(object.objectName) {
+				return
+			}
+			history.ObjectNames.Add(object.objectName)
+		}
+
+		// Objects following a commit were introduced by it
+		if object.objectType == "commit" {
+			month := currentMonth
+			if timestamp, err := strconv.ParseInt(object.rest, 10, 64); err == nil {
+				month = models.MonthInterval.PeriodOf(time.Unix(timestamp, 0))
+			}
+			if month.Index > currentMonth.Index && progress != nil {
+				progress(month, running)
+			}
+			currentMonth = month
+			current = history.objectsOf(month)
+		}
+		if current == nil {
+			current = history.objectsOf(currentMonth)
+		}
+
+		current.Compressed += object.compressedSize
+		current.Uncompressed += object.uncompressedSize
+		running.Compressed += object.compressedSize
+		running.Uncompressed += object.uncompressedSize
+
+		switch object.objectType {
+		case "commit":
+			current.Commits++
+			running.Commits++
+		case "tree":
+			current.Trees++
+			running.Trees++
+		case "blob":
+			current.Blobs++
+			running.Blobs++
+			path := object.rest
+			if key, ok := ParseObjectKey(object.objectName); ok {
+				if newestPath, found := newestPaths[key]; found {
+					path = newestPath
+				}
+			}
+			if path != "" {
+				file := current.Files[path]
+				file.Path = path
+				file.Blobs++
+				file.CompressedSize += object.compressedSize
+				file.UncompressedSize += object.uncompressedSize
+				file.LargestBlob = max(file.LargestBlob, object.uncompressedSize)
+				// LastChange remains zero as we do not parse it here
+				current.Files[path] = file
+			}
+		}
+	})
+}
+
+// addsWalkedBlobs reports whether the commits reachable from revisions add a blob of the history
+// under any path, which includes renaming, moving and restoring a file
+func (repository Repository) addsWalkedBlobs(ctx context.Context, history *History, revisions []string) (bool, error) {
+	arguments := append([]string{"log", "--raw", "--no-renames", "--no-abbrev", "--format="}, repository.CommitLimits()...)
+	output, err := repository.output(ctx, revisionInput(revisions), append(arguments, "--stdin")...)
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		// Changes are listed as ":<old mode> <new mode> <old name> <new name> <status>\t<path>"
+		change, _, found := strings.Cut(line, "\t")
+		if fields := strings.Fields(change); found && len(fields) == 5 && history.ObjectNames.Contains(fields[3]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updateContributors counts the commits of each author and committer reachable from revisions
+func (repository Repository) updateContributors(ctx context.Context, history *History, revisions []string) error {
+	arguments := append([]string{"log", "--format=%an%x00%cn%x00%cd", "--date=format:%Y-%m"}, repository.CommitLimits()...)
+	output, err := repository.output(ctx, revisionInput(revisions), append(arguments, "--stdin")...)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Names are separated by NUL as they may contain any other character
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 3 {
+			continue
+		}
+
+		month, err := models.ParsePeriod(parts[2])
+		if err != nil || month.Interval != models.MonthInterval {
+			continue
+		}
+
+		contributors := history.contributorsOf(month)
+		contributors.Commits++
+		contributors.Authors[parts[0]]++
+		contributors.Committers[parts[1]]++
+	}
+	return nil
+}
+
+// GrowthStatistics returns the cumulative growth statistics for every period from first to last,
+// which determine the interval. Objects introduced by commits dated outside of the range count
+// towards the nearest period within it.
+func (history *History) GrowthStatistics(first, last models.Period) map[models.Period]models.GrowthStatistics {
+	objectsByPeriod := make(map[int][]*MonthlyObjects)
+	for month, objects := range history.Objects {
+		index := min(max(month.In(first.Interval).Index, first.Index), last.Index)
+		objectsByPeriod[index] = append(objectsByPeriod[index], objects)
+	}
+
+	// Accumulate the objects of each period onto the totals of the previous periods
+	periodStatistics := make(map[models.Period]models.GrowthStatistics)
+	var cumulative models.GrowthStatistics
+	cumulativeFiles := make(map[string]models.FileInformation)
+	for period := first; period.Index <= last.Index; period = period.Add(1) {
+		cumulative.Period = period
+		for _, objects := range objectsByPeriod[period.Index] {
+			cumulative.Commits += objects.Commits
+			cumulative.Trees += objects.Trees
+			cumulative.Blobs += objects.Blobs
+			cumulative.Compressed += objects.Compressed
+			cumulative.Uncompressed += objects.Uncompressed
+
+			for path, file := range objects.Files {
+				existing := cumulativeFiles[path]
+				existing.Path = path
+				existing.Blobs += file.Blobs
+				existing.CompressedSize += file.CompressedSize
+				existing.UncompressedSize += file.UncompressedSize
+				existing.LargestBlob = max(existing.LargestBlob, file.LargestBlob)
+				cumulativeFiles[path] = existing
+			}
+		}
+		cumulative.LargestFiles = make([]models.FileInformation, 0, len(cumulativeFiles))
+		for _, file := range cumulativeFiles {
+			cumulative.LargestFiles = append(cumulative.LargestFiles, file)
+		}
+
+		periodStatistics[period] = cumulative
+	}
+	return periodStatistics
+}
+
+// Split returns the part of the history before month and the part from month on. Both share the
+// statistics of each month with the history and have no tips.
+func (history *History) Split(month models.Period) (before, after *History) {
+	before, after = NewHistory(), NewHistory()
+	for objectsMonth, objects := range history.Objects {
+		if objectsMonth.Index < month.Index {
+			before.Objects[objectsMonth] = objects
+		} else {
+			after.Objects[objectsMonth] = objects
+		}
+	}
+	for contributorsMonth, contributors := range history.Contributors {
+		if contributorsMonth.Index < month.Index {
+			before.Contributors[contributorsMonth] = contributors
+		} else {
+			after.Contributors[contributorsMonth] = contributors
+		}
+	}
+	return before, after
+}
+
+// contributorsByPeriod returns the contributors of each period of the interval
+func (history *History) contributorsByPeriod(interval models.Interval) map[models.Period]*MonthlyContributors {
+	if interval == models.MonthInterval {
+		return history.Contributors
+	}
+	periods := make(map[models.Period]*MonthlyContributors)
+	for month, contributors := range history.Contributors {
+		period := month.In(interval)
+		merged, exists := periods[period]
+		if !exists {
+			merged = &MonthlyContributors{Authors: make(map[string]int), Committers: make(map[string]int)}
+			periods[period] = merged
+		}
+		merged.Commits += contributors.Commits
+		for author, commits := range contributors.Authors {
+			merged.Authors[author] += commits
+		}
+		for committer, commits := range contributors.Committers {
+			merged.Committers[committer] += commits
+		}
+	}
+	return periods
+}
+
+// TopCommitAuthors returns the top N commit authors and committers by number of commits, grouped by period of the interval
+func (history *History) TopCommitAuthors(n int, interval models.Interval) (map[models.Period][][3]string, map[models.Period]int, map[models.Period]int, map[models.Period][][3]string, map[models.Period]int, map[string]int, map[string]int) {
+	authorResult := make(map[models.Period][][3]string)
+	committerResult := make(map[models.Period][][3]string)
+	totalAuthorsByPeriod := make(map[models.Period]int)
+	totalCommittersByPeriod := make(map[models.Period]int)
+	totalCommitsByPeriod := make(map[models.Period]int)
+
+	// Maps to track all unique authors and committers across all periods
+	allTimeAuthors := make(map[string]int)
+	allTimeCommitters := make(map[string]int)
+
+	for period, contributors := range history.contributorsByPeriod(interval) {
+		totalCommitsByPeriod[period] = contributors.Commits
+		authorResult[period], totalAuthorsByPeriod[period] = processContributors(contributors.Authors, n, period)
+		committerResult[period], totalCommittersByPeriod[period] = processContributors(contributors.Committers, n, period)
+
+		for author, commits := range contributors.Authors {
+			allTimeAuthors[author] += commits
+		}
+		for committer, commits := range contributors.Committers {
+			allTimeCommitters[committer] += commits
+		}
+	}
+
+	return authorResult, totalAuthorsByPeriod, totalCommitsByPeriod, committerResult, totalCommittersByPeriod, allTimeAuthors, allTimeCommitters
+}
+
+// CumulativeUniqueAuthors returns a map of period of the interval -> cumulative unique author count
+// along with the final total unique authors across all periods.
+func (history *History) CumulativeUniqueAuthors(interval models.Interval) (map[models.Period]int, int) {
+	contributorsByPeriod := history.contributorsByPeriod(interval)
+	var periods []models.Period
+	for period := range contributorsByPeriod {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
+
+	cumulativeCounts := make(map[models.Period]int)
+	cumulativeSet := make(map[string]struct{})
+	for _, period := range periods {
+		for author := range contributorsByPeriod[period].Authors {
+			cumulativeSet[author] = struct{}{}
+		}
+		cumulativeCounts[period] = len(cumulativeSet)
+	}
+	return cumulativeCounts, len(cumulativeSet)
+}
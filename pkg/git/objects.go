@@ -0,0 +1,123 @@
+package git
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+)
+
+// ObjectSet is a set of object names stored as sorted binary names. It needs a fraction of the
+// memory of a map keyed by hexadecimal names and supports SHA-1 as well as SHA-256 repositories.
+type ObjectSet struct {
+	NameLength int    // Length of a binary object name, 20 for SHA-1 and 32 for SHA-256
+	Names      []byte // Sorted binary object names
+
+	added []byte // Names added since the last call to Merge, in the order they were added
+}
+
+// NewObjectSet returns an empty object set
+func NewObjectSet() *ObjectSet {
+	return &ObjectSet{}
+}
+
+// decode returns the binary form of the hexadecimal object name or false if it is not a valid name
+// for the set
+func (set *ObjectSet) decode(name string, buffer []byte) ([]byte, bool) {
+	length := hex.DecodedLen(len(name))
+	if length > len(buffer) || (set.NameLength != 0 && length != set.NameLength) {
+		return nil, false
+	}
+	if _, err := hex.Decode(buffer[:length], []byte(name)); err != nil {
+		return nil, false
+	}
+	return buffer[:length], true
+}
+
+// ObjectKey is the binary form of an object name for use as map key. It holds SHA-1 as well as
+// SHA-256 names, shorter names are padded with zeros.
+type ObjectKey [32]byte
+
+// ParseObjectKey returns the key of the hexadecimal object name or false if it is not a valid name
+func ParseObjectKey(name string) (ObjectKey, bool) {
+	var key ObjectKey
+	_, ok := (&ObjectSet{}).decode(name, key[:])
+	return key, ok
+}
+
+// Contains reports whether the hexadecimal object name was in the set when Merge was last called
+func (set *ObjectSet) Contains(name string) bool {
+	var buffer [32]byte
+	binaryName, ok := set.decode(name, buffer[:])
+	if !ok || set.NameLength == 0 {
+		return false
+	}
+	count := len(set.Names) / set.NameLength
+	index := sort.Search(count, func(index int) bool {
+		return bytes.Compare(set.name(set.Names, index), binaryName) >= 0
+	})
+	return index < count && bytes.Equal(set.name(set.Names, index), binaryName)
+}
+
+// Add adds the hexadecimal object name to the set. It is found by Contains after the next Merge.
+func (set *ObjectSet) Add(name string) {
+	var buffer [32]byte
+	binaryName, ok := set.decode(name, buffer[:])
+	if !ok {
+		return
+	}
+	set.NameLength = len(binaryName)
+	set.added = append(set.added, binaryName...)
+}
+
+// Merge sorts the names added since the last call into the set
+func (set *ObjectSet) Merge() {
+	if len(set.added) == 0 {
+		return
+	}
+	sort.Sort(sortableNames{names: set.added, nameLength: set.NameLength})
+
+	merged := make([]byte, 0, len(set.Names)+len(set.added))
+	existing, added := set.Names, set.added
+	for len(existing) > 0 && len(added) > 0 {
+		if bytes.Compare(existing[:set.NameLength], added[:set.NameLength]) <= 0 {
+			merged = append(merged, existing[:set.NameLength]...)
+			existing = existing[set.NameLength:]
+		} else {
+			merged = append(merged, added[:set.NameLength]...)
+			added = added[set.NameLength:]
+		}
+	}
+	merged = append(merged, existing...)
+	merged = append(merged, added...)
+
+	set.Names = merged
+	set.added = nil
+}
+
+// name returns the binary object name at index within names
+func (set *ObjectSet) name(names []byte, index int) []byte {
+	return names[index*set.NameLength : (index+1)*set.NameLength]
+}
+
+// sortableNames sorts concatenated binary object names of equal length
+type sortableNames struct {
+	names      []byte
+	nameLength int
+}
+
+func (names sortableNames) Len() int {
+	return len(names.names) / names.nameLength
+}
+
+func (names sortableNames) Less(i, j int) bool {
+	return bytes.Compare(names.names[i*names.nameLength:(i+1)*names.nameLength], names.names[j*names.nameLength:(j+1)*names.nameLength]) < 0
+}
+
+func (names sortableNames) Swap(i, j int) {
+	var buffer [32]byte
+	first := names.names[i*names.nameLength : (i+1)*names.nameLength]
+	second := names.names[j*names.nameLength : (j+1)*names.nameLength]
+	copy(buffer[:], first)
+	copy(first, second)
+	copy(second, buffer[:names.nameLength])
+}
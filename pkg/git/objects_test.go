@@ -0,0 +1,49 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObjectSet(t *testing.T) {
+	for _, nameLength := range []int{40, 64} { // SHA-1 and SHA-256
+		set := NewObjectSet()
+		first := strings.Repeat("b", nameLength)
+		second := strings.Repeat("a", nameLength)
+		third := strings.Repeat("c", nameLength)
+
+		set.Add(first)
+		if set.Contains(first) {
+			t.Errorf("expected %s not to be found before Merge", first)
+		}
+		set.Merge()
+		set.Add(third)
+		set.Add(second)
+		set.Merge()
+
+		for _, name := range []string{first, second, third} {
+			if !set.Contains(name) {
+				t.Errorf("expected set to contain %s", name)
+			}
+		}
+		if set.Contains(strings.Repeat("d", nameLength)) || set.Contains("not a name") {
+			t.Errorf("expected set not to contain names that were not added")
+		}
+		if len(set.Names) != 3*nameLength/2 {
+			t.Errorf("expected %d bytes for 3 names, got %d", 3*nameLength/2, len(set.Names))
+		}
+	}
+}
+
+func TestParseObjectKey(t *testing.T) {
+	first, ok := ParseObjectKey(strings.Repeat("ab", 20))
+	second, _ := ParseObjectKey(strings.Repeat("ab", 32))
+	if !ok || first[0] != 0xab || first[19] != 0xab || first[20] != 0 || first == second {
+		t.Errorf("expected the binary SHA-1 name padded with zeros, got %x", first)
+	}
+	for _, name := range []string{"not a name", "abc", strings.Repeat("a", 66)} {
+		if _, ok := ParseObjectKey(name); ok {
+			t.Errorf("expected %q not to be parsed", name)
+		}
+	}
+}
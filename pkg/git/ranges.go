@@ -0,0 +1,158 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"git-metrics/pkg/models"
+)
+
+// ErrInvalidRange is returned for a range that is not of the form A..B
+var ErrInvalidRange = errors.New("invalid range")
+
+// ErrInvalidRefUpdate is returned for a line that is neither in the pre-receive nor in the pre-push format
+var ErrInvalidRefUpdate = errors.New("invalid ref update")
+
+// ErrUnknownRevision is returned for a revision of a range that does not name an object of the repository
+var ErrUnknownRevision = errors.New("unknown revision")
+
+// Range selects the objects reachable from Include but neither from Exclude nor from the refs
+// matching ExcludeRefs, such as the objects a push adds to a repository
+type Range struct {
+	Names       []string // Ranges or updated refs as given, for example main~3..main
+	Include     []string // Revisions whose objects are measured
+	Exclude     []string // Revisions whose objects are not measured
+	ExcludeRefs []string // Patterns of refs whose objects are already in the repository, none if empty
+}
+
+// ParseRange parses a range like main~3..main into the objects reachable from main but not from main~3
+func ParseRange(value string) (Range, error) {
+	excluded, included, found := strings.Cut(strings.TrimSpace(value), "..")
+	if !found || excluded == "" || included == "" || strings.HasPrefix(included, ".") {
+		return Range{}, fmt.Errorf("%w %q", ErrInvalidRange, value)
+	}
+	return Range{Names: []string{value}, Include: []string{included}, Exclude: []string{excluded}}, nil
+}
+
+// ParseRefUpdates reads the refs a push updates in the format git passes them to hooks on standard
+// input: "<old> <new> <ref>" for pre-receive hooks on the receiving repository and "<local ref>
+// <local object> <remote ref> <remote object>" for pre-push hooks on the pushing one. Deleted refs
+// add no objects and are skipped. Objects reachable from any ref of the receiving repository, or
+// from a remote-tracking ref of the pushing one, are already on the receiving side and not measured.
+func ParseRefUpdates(reader io.Reader) (Range, error) {
+	var updates Range
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 0:
+			continue
+		case 3:
+			updates.ExcludeRefs = []string{"refs"}
+			if isZeroObjectName(fields[1]) {
+				continue
+			}
+			updates.Names = append(updates.Names, fields[2])
+			updates.Include = append(updates.Include, fields[1])
+			if !isZeroObjectName(fields[0]) {
+				updates.Exclude = append(updates.Exclude, fields[0])
+			}
+		case 4:
+			// The remote object may be missing locally, the remote-tracking refs exclude it if not
+			updates.ExcludeRefs = []string{"refs/remotes"}
+			if isZeroObjectName(fields[1]) {
+				continue
+			}
+			updates.Names = append(updates.Names, fields[2])
+			updates.Include = append(updates.Include, fields[1])
+		default:
+			return Range{}, fmt.Errorf("%w %q", ErrInvalidRefUpdate, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Range{}, err
+	}
+	return updates, nil
+}
+
+// isZeroObjectName reports whether the name is the null object git passes for created and deleted refs
+func isZeroObjectName(name string) bool {
+	return strings.Trim(name, "0") == ""
+}
+
+// MeasureRange returns the totals of the objects the range selects together with the files of
+// its blobs, measured with the same cat-file sizes as the history. The files are sorted by the
+// size of their largest blob, largest first.
+func (repository Repository) MeasureRange(ctx context.Context, objects Range) (models.GrowthStatistics, error) {
+	var statistics models.GrowthStatistics
+	if len(objects.Include) == 0 {
+		return statistics, nil
+	}
+
+	// rev-list fails without telling which revision it does not know
+	for _, revision := range append(append([]string{}, objects.Include...), objects.Exclude...) {
+		if _, err := repository.RunGitCommand(ctx, "rev-parse", "--verify", "--quiet", revision+"^{object}"); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return statistics, ctxErr
+			}
+			return statistics, fmt.Errorf("%w %q", ErrUnknownRevision, revision)
+		}
+	}
+
+	excluded := objects.Exclude
+	if len(objects.ExcludeRefs) > 0 {
+		refs := repository
+		refs.Refs = RefScope{Include: objects.ExcludeRefs}
+		tips, err := refs.GetRefTips(ctx)
+		if err != nil {
+			return statistics, err
+		}
+		excluded = append(append([]string{}, excluded...), tips...)
+	}
+
+	files := make(map[string]models.FileInformation)
+	err := repository.ListObjects(ctx, excluding(objects.Include, excluded), func(line string) {
+		object, ok := parseObjectInformation(line)
+		if !ok {
+			return // Skip missing objects and invalid size entries
+		}
+		statistics.Compressed += object.compressedSize
+		statistics.Uncompressed += object.uncompressedSize
+		switch object.objectType {
+		case "commit":
+			statistics.Commits++
+		case "tree":
+			statistics.Trees++
+		case "blob":
+			statistics.Blobs++
+			if object.rest != "" {
+				file := files[object.rest]
+				file.Path = object.rest
+				file.Blobs++
+				file.CompressedSize += object.compressedSize
+				file.UncompressedSize += object.uncompressedSize
+				file.LargestBlob = max(file.LargestBlob, object.uncompressedSize)
+				files[object.rest] = file
+			}
+		}
+	})
+	if err != nil {
+		return statistics, err
+	}
+
+	for _, file := range files {
+		statistics.LargestFiles = append(statistics.LargestFiles, file)
+	}
+	sort.Slice(statistics.LargestFiles, func(i, j int) bool {
+		if statistics.LargestFiles[i].LargestBlob != statistics.LargestFiles[j].LargestBlob {
+			return statistics.LargestFiles[i].LargestBlob > statistics.LargestFiles[j].LargestBlob
+		}
+		return statistics.LargestFiles[i].Path < statistics.LargestFiles[j].Path
+	})
+	return statistics, nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	objects, err := ParseRange("main~3..main")
+	if err != nil || !slices.Equal(objects.Include, []string{"main"}) || !slices.Equal(objects.Exclude, []string{"main~3"}) {
+		t.Errorf("ParseRange(main~3..main) = %+v, %v", objects, err)
+	}
+	for _, value := range []string{"main", "..main", "main..", "main...feature"} {
+		if _, err := ParseRange(value); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("ParseRange(%s): expected ErrInvalidRange, got %v", value, err)
+		}
+	}
+}
+
+func TestParseRefUpdates(t *testing.T) {
+	zero := strings.Repeat("0", 40)
+	old, updated := strings.Repeat("a", 40), strings.Repeat("b", 40)
+
+	// pre-receive: old, new and ref, deleted refs are skipped
+	input := old + " " + updated + " refs/heads/main\n" + zero + " " + updated + " refs/heads/feature\n" + old + " " + zero + " refs/heads/gone\n"
+	updates, err := ParseRefUpdates(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRefUpdates() error = %v", err)
+	}
+	if !slices.Equal(updates.Names, []string{"refs/heads/main", "refs/heads/feature"}) || !slices.Equal(updates.Include, []string{updated, updated}) ||
+		!slices.Equal(updates.Exclude, []string{old}) || !slices.Equal(updates.ExcludeRefs, []string{"refs"}) {
+		t.Errorf("unexpected pre-receive updates %+v", updates)
+	}
+
+	// pre-push: local ref, local object, remote ref and remote object
+	updates, err = ParseRefUpdates(strings.NewReader("refs/heads/main " + updated + " refs/heads/main " + old + "\n"))
+	if err != nil || !slices.Equal(updates.Include, []string{updated}) || len(updates.Exclude) != 0 || !slices.Equal(updates.ExcludeRefs, []string{"refs/remotes"}) {
+		t.Errorf("unexpected pre-push updates %+v, %v", updates, err)
+	}
+
+	if _, err := ParseRefUpdates(strings.NewReader(updated + " refs/heads/main\n")); !errors.Is(err, ErrInvalidRefUpdate) {
+		t.Errorf("expected ErrInvalidRefUpdate, got %v", err)
+	}
+}
+
+func TestMeasureRange(t *testing.T) {
+	repositoryPath := t.TempDir()
+	if output, err := exec.Command("git", "init", "--quiet", repositoryPath).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	repository := Repository{Path: repositoryPath}
+	commitFile(t, repositoryPath, "README.md", "first", "2023-06-01T12:00:00Z")
+	commitFile(t, repositoryPath, "assets/video.mp4", strings.Repeat("frame", 1000), "2024-06-01T12:00:00Z")
+	objectName := func(revision string) string {
+		output, err := repository.RunGitCommand(context.Background(), "rev-parse", revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	first, second := objectName("HEAD~1"), objectName("HEAD")
+
+	// The second commit adds a root and an assets tree and the video
+	objects, _ := ParseRange(first + ".." + second)
+	statistics, err := repository.MeasureRange(context.Background(), objects)
+	if err != nil {
+		t.Fatalf("MeasureRange() error = %v", err)
+	}
+	if statistics.Commits != 1 || statistics.Trees != 2 || statistics.Blobs != 1 || statistics.Compressed == 0 {
+		t.Errorf("expected 1 commit, 2 trees and 1 blob, got %+v", statistics)
+	}
+	if len(statistics.LargestFiles) != 1 || statistics.LargestFiles[0].Path != "assets/video.mp4" || statistics.LargestFiles[0].LargestBlob != 5000 {
+		t.Errorf("expected the video as only new file, got %+v", statistics.LargestFiles)
+	}
+
+	// A push of commits the refs already contain adds nothing
+	updates, _ := ParseRefUpdates(strings.NewReader(first + " " + second + " refs/heads/feature\n"))
+	if statistics, err := repository.MeasureRange(context.Background(), updates); err != nil || statistics.Commits != 0 || statistics.Blobs != 0 {
+		t.Errorf("expected no objects beyond the refs, got %+v, %v", statistics, err)
+	}
+
+	unknown, _ := ParseRange(first + "..missing")
+	if _, err := repository.MeasureRange(context.Background(), unknown); !errors.Is(err, ErrUnknownRevision) {
+		t.Errorf("expected ErrUnknownRevision, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RefScope selects the refs whose history is analyzed
+type RefScope struct {
+	Include []string // Patterns of the analyzed refs, all refs and HEAD if empty
+	Exclude []string // Patterns of refs that are left out even if included
+}
+
+// ParseRefScope returns the scope of the given patterns. Patterns prefixed with ^ exclude refs.
+// A pattern is a glob such as refs/heads/* and also matches all refs below the refs it matches,
+// so refs/remotes/* matches refs/remotes/origin/main.
+func ParseRefScope(patterns []string) (RefScope, error) {
+	var scope RefScope
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		excluded := strings.HasPrefix(pattern, "^")
+		pattern = strings.TrimPrefix(pattern, "^")
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return RefScope{}, fmt.Errorf("invalid ref pattern %q: %w", pattern, err)
+		}
+		if excluded {
+			scope.Exclude = append(scope.Exclude, pattern)
+		} else {
+			scope.Include = append(scope.Include, pattern)
+		}
+	}
+	return scope, nil
+}
+
+// IsAll reports whether the scope contains all refs and HEAD
+func (scope RefScope) IsAll() bool {
+	return len(scope.Include) == 0 && len(scope.Exclude) == 0
+}
+
+// Matches reports whether the ref, for example refs/heads/main or HEAD, is within the scope
+func (scope RefScope) Matches(ref string) bool {
+	if len(scope.Include) > 0 && !matchesAny(scope.Include, ref) {
+		return false
+	}
+	return !matchesAny(scope.Exclude, ref)
+}
+
+// String describes the scope, for example "refs/heads/*, refs/tags/* excluding refs/tags/nightly-*"
+func (scope RefScope) String() string {
+	description := "all refs"
+	if len(scope.Include) > 0 {
+		description = strings.Join(scope.Include, ", ")
+	}
+	if len(scope.Exclude) > 0 {
+		description += " excluding " + strings.Join(scope.Exclude, ", ")
+	}
+	return description
+}
+
+// matchesAny reports whether any pattern matches the ref or one of the refs it is below
+func matchesAny(patterns []string, ref string) bool {
+	for _, pattern := range patterns {
+		for prefix := ref; prefix != ""; {
+			if matched, _ := path.Match(pattern, prefix); matched {
+				return true
+			}
+			index := strings.LastIndex(prefix, "/")
+			if index < 0 {
+				break
+			}
+			prefix = prefix[:index]
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestRefScope(t *testing.T) {
+	scope, err := ParseRefScope([]string{"refs/heads/*", " refs/tags ", "^refs/tags/nightly-*", ""})
+	if err != nil {
+		t.Fatalf("ParseRefScope() error = %v", err)
+	}
+	if description := scope.String(); description != "refs/heads/*, refs/tags excluding refs/tags/nightly-*" {
+		t.Errorf("unexpected description %q", description)
+	}
+
+	tests := []struct {
+		ref      string
+		expected bool
+	}{
+		{"refs/heads/main", true},
+		{"refs/heads/feature/login", true},
+		{"refs/tags/v1.0", true},
+		{"refs/tags/nightly-2024", false},
+		{"refs/remotes/origin/main", false},
+		{"HEAD", false},
+	}
+	for _, test := range tests {
+		if matched := scope.Matches(test.ref); matched != test.expected {
+			t.Errorf("Matches(%q) = %v, expected %v", test.ref, matched, test.expected)
+		}
+	}
+
+	all, _ := ParseRefScope(nil)
+	if !all.IsAll() || !all.Matches("HEAD") || all.String() != "all refs" {
+		t.Errorf("expected an empty scope to contain all refs, got %+v", all)
+	}
+
+	if _, err := ParseRefScope([]string{"refs/heads/["}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
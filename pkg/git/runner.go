@@ -0,0 +1,118 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned by a ReplayingRunner for a command it has no recording of
+var ErrNotRecorded = errors.New("git command was not recorded")
+
+// Runner runs git commands. All git access of a Repository goes through its runner, so tests can
+// serve canned outputs instead of building real repositories.
+type Runner interface {
+	// Run runs git with args inside directory and writes its standard output to stdout. It reads
+	// the standard input from stdin unless stdin is nil. The command is stopped once the context
+	// is done.
+	Run(ctx context.Context, directory string, stdin io.Reader, stdout io.Writer, args ...string) error
+}
+
+// ExecRunner runs the git executable found in the PATH
+type ExecRunner struct{}
+
+// Run runs git as a child process that is killed once the context is done
+func (ExecRunner) Run(ctx context.Context, directory string, stdin io.Reader, stdout io.Writer, args ...string) error {
+	command := exec.CommandContext(ctx, "git", args...)
+	command.Dir = directory
+	command.Stdin = stdin
+	command.Stdout = stdout
+	return command.Run()
+}
+
+// Recording is a git command together with its input and the output it produced
+type Recording struct {
+	Args   []string `json:"args"`
+	Stdin  string   `json:"stdin,omitempty"`
+	Stdout string   `json:"stdout"`
+	Error  string   `json:"error,omitempty"` // Empty if the command succeeded
+}
+
+// RecordingRunner runs commands with another runner and records them, so they can be served by
+// a ReplayingRunner later
+type RecordingRunner struct {
+	Runner Runner // Runs the recorded commands, ExecRunner if nil
+
+	mutex      sync.Mutex
+	recordings []Recording
+}
+
+// Run runs the command with the underlying runner and records its input and output
+func (runner *RecordingRunner) Run(ctx context.Context, directory string, stdin io.Reader, stdout io.Writer, args ...string) error {
+	var input, output bytes.Buffer
+	if stdin != nil {
+		stdin = io.TeeReader(stdin, &input)
+	}
+	underlying := runner.Runner
+	if underlying == nil {
+		underlying = ExecRunner{}
+	}
+	err := underlying.Run(ctx, directory, stdin, io.MultiWriter(stdout, &output), args...)
+
+	recording := Recording{Args: slices.Clone(args), Stdin: input.String(), Stdout: output.String()}
+	if err != nil {
+		recording.Error = err.Error()
+	}
+	runner.mutex.Lock()
+	runner.recordings = append(runner.recordings, recording)
+	runner.mutex.Unlock()
+	return err
+}
+
+// Recordings returns the commands run so far in the order they finished
+func (runner *RecordingRunner) Recordings() []Recording {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	return slices.Clone(runner.recordings)
+}
+
+// ReplayingRunner serves the output of recorded commands instead of running git. A command is
+// served by the first recording with the same arguments whose input is either empty or equal to
+// the command's input, so canned recordings can leave out long inputs.
+type ReplayingRunner struct {
+	Recordings []Recording
+}
+
+// Run writes the recorded output of the command to stdout and returns the recorded error
+func (runner *ReplayingRunner) Run(ctx context.Context, directory string, stdin io.Reader, stdout io.Writer, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var input []byte
+	if stdin != nil {
+		var err error
+		if input, err = io.ReadAll(stdin); err != nil {
+			return err
+		}
+	}
+
+	for _, recording := range runner.Recordings {
+		if !slices.Equal(recording.Args, args) || (recording.Stdin != "" && recording.Stdin != string(input)) {
+			continue
+		}
+		if _, err := io.WriteString(stdout, recording.Stdout); err != nil {
+			return err
+		}
+		if recording.Error != "" {
+			return errors.New(recording.Error)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: git %s", ErrNotRecorded, strings.Join(args, " "))
+}
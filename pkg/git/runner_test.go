@@ -0,0 +1,34 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestReplayingRecordedCommands(t *testing.T) {
+	repositoryPath := t.TempDir()
+	if output, err := exec.Command("git", "init", "--quiet", repositoryPath).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	commitFile(t, repositoryPath, "docs/release notes.md", "first", "2022-06-01T12:00:00Z")
+	commitFile(t, repositoryPath, "README.md", "second", "2023-06-01T12:00:00Z")
+
+	recorder := &RecordingRunner{}
+	recorded := NewHistory()
+	walkHistory(t, Repository{Path: repositoryPath, Runner: recorder}, recorded, nil)
+
+	// Replaying needs neither git nor the repository
+	replayed := NewHistory()
+	walkHistory(t, Repository{Path: t.TempDir(), Runner: &ReplayingRunner{Recordings: recorder.Recordings()}}, replayed, nil)
+	if !reflect.DeepEqual(recorded.Objects, replayed.Objects) || !reflect.DeepEqual(recorded.Contributors, replayed.Contributors) {
+		t.Errorf("expected the replayed history to match the recorded one, got %+v and %+v", replayed.Objects, recorded.Objects)
+	}
+
+	_, err := Repository{Runner: &ReplayingRunner{}}.RunGitCommand(context.Background(), "status")
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded for a command that was not recorded, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	runtimemetrics "runtime/metrics"
+	"time"
+)
+
+// memorySampleInterval is how often the heap size is sampled while a stage runs
+const memorySampleInterval = 20 * time.Millisecond
+
+// heapObjectsMetric is the runtime metric for the heap memory occupied by reachable objects
+// and unreachable objects the garbage collector has not freed yet
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// measurePeakMemory runs collect and returns the largest heap size sampled while it ran.
+// Reading runtime metrics does not stop the world, so sampling barely slows down collect.
+func measurePeakMemory(collect func()) uint64 {
+	done := make(chan struct{})
+	peak := make(chan uint64)
+	go func() {
+		largest := heapSize()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				largest = max(largest, heapSize())
+			case <-done:
+				peak <- max(largest, heapSize())
+				return
+			}
+		}
+	}()
+
+	collect()
+	close(done)
+	return <-peak
+}
+
+// heapSize returns the current heap size or 0 if the runtime does not support the metric
+func heapSize() uint64 {
+	samples := []runtimemetrics.Sample{{Name: heapObjectsMetric}}
+	runtimemetrics.Read(samples)
+	if samples[0].Value.Kind() != runtimemetrics.KindUint64 {
+		return 0
+	}
+	return samples[0].Value.Uint64()
+}
@@ -0,0 +1,846 @@
+// Package metrics analyzes a git repository and returns all collected metrics as a report.
+// It does not write to standard output, change the working directory or exit the process,
+// so it can be used to analyze many repositories from within another Go program.
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"git-metrics/pkg/cache"
+	"git-metrics/pkg/display/sections"
+	"git-metrics/pkg/git"
+	"git-metrics/pkg/models"
+	"git-metrics/pkg/utils"
+)
+
+// UnknownValue is used for repository information that could not be determined
+const UnknownValue = "Unknown"
+
+// ErrNoCommits is returned when the repository does not contain any commits
+var ErrNoCommits = errors.New("no commits found in the repository")
+
+// ErrNoMatchingRefs is returned when no ref matches the patterns of Options.Refs
+var ErrNoMatchingRefs = errors.New("no refs match")
+
+// Options configure an analysis
+type Options struct {
+	RepositoryPath string     // Path to the repository, defaults to the current directory
+	Debug          bool       // Print executed git commands to stderr
+	Cache          bool       // Continue the history cached in the Git directory and update the cache
+	Jobs           int        // Maximum number of collectors running at the same time, defaults to the number of CPU cores
+	Observer       Observer   // Notified about progress while analyzing, may be nil
+	Runner         git.Runner // Runs the git commands, the git executable if nil
+
+	// Analyze only the history of the refs within this scope, all refs and HEAD if empty. The
+	// cache is not used for a limited scope.
+	Refs git.RefScope
+
+	// Analyze the repository as it looked at this time, considering only commits up to then and
+	// estimating growth relative to it. The current time if zero. The cache is not used.
+	AsOf  time.Time
+	Clock func() time.Time // Returns the current time, time.Now if nil
+
+	// Report only what happened from the start of the month of this time on, the whole history if
+	// zero. Totals still include the earlier history, which is walked or read from the cache.
+	Since time.Time
+
+	// Group growth, estimates, rates of changes and contributors by this interval, by year if empty
+	Interval models.Interval
+
+	// Project the growth of the periods following the current one with this model, linearly if empty
+	ForecastModel models.ForecastModel
+
+	// Evaluate the levels of concern with these thresholds, those of the default profile if nil
+	ConcernThresholds models.ConcernThresholds
+
+	// Replay all forecast models at the end of each past period and report their errors
+	Backtest bool
+
+	// Collect only these of the models.SelectableSections in this order, all in their default order
+	// if empty. The history is walked only for selected sections that need it, and the backtest is
+	// only collected if Backtest is set as well.
+	Sections []models.Section
+
+	// Limit how many entries the sections list and how far the estimates reach, see DefaultLimits
+	Limits Limits
+
+	// Check the collected metrics against the limits of this policy and report the violations
+	Policy *models.Policy
+
+	// Measure only the objects this range adds, such as the objects of a push, instead of the
+	// history. The report then only contains the run information, the range and the policy check.
+	Range *git.Range
+}
+
+// Limits configure how many entries the sections list and how far they reach. Zero values are
+// replaced by those of DefaultLimits.
+type Limits struct {
+	FileExtensions     int     // Largest file extensions
+	GrowingExtensions  int     // File extensions with the most on-disk growth per period
+	Files              int     // Largest files
+	Contributors       int     // Authors and committers with the most commits per period
+	ForecastPeriods    int     // Periods following the current one the growth is estimated for
+	DirectoryDepth     int     // Levels of directories below the root in the largest directories
+	DirectoryThreshold float64 // Percentage of the on-disk size from which on directories and files are listed
+}
+
+// DefaultLimits are the limits used unless configured otherwise
+var DefaultLimits = Limits{
+	FileExtensions:     10,
+	GrowingExtensions:  3,
+	Files:              10,
+	Contributors:       3,
+	ForecastPeriods:    5,
+	DirectoryDepth:     sections.DefaultMaxDirectoryDepth,
+	DirectoryThreshold: sections.DefaultDirectoryThreshold,
+}
+
+// orDefaults returns the limits with zero values replaced by those of DefaultLimits
+func (limits Limits) orDefaults() Limits {
+	limits.FileExtensions = cmp.Or(limits.FileExtensions, DefaultLimits.FileExtensions)
+	limits.GrowingExtensions = cmp.Or(limits.GrowingExtensions, DefaultLimits.GrowingExtensions)
+	limits.Files = cmp.Or(limits.Files, DefaultLimits.Files)
+	limits.Contributors = cmp.Or(limits.Contributors, DefaultLimits.Contributors)
+	limits.ForecastPeriods = cmp.Or(limits.ForecastPeriods, DefaultLimits.ForecastPeriods)
+	limits.DirectoryDepth = cmp.Or(limits.DirectoryDepth, DefaultLimits.DirectoryDepth)
+	limits.DirectoryThreshold = cmp.Or(limits.DirectoryThreshold, DefaultLimits.DirectoryThreshold)
+	return limits
+}
+
+// Observer is notified while an analysis progresses, so callers can present each
+// section as soon as its data is available instead of waiting for the complete report
+type Observer interface {
+	// StartSection is called before the data of a section is collected
+	StartSection(section models.Section)
+	// StartPeriod is called before the growth of a period is collected together with the
+	// cumulative statistics of the two preceding periods
+	StartPeriod(period models.Period, previous models.GrowthStatistics, beforePrevious models.GrowthStatistics)
+	// FinishPeriod is called with the cumulative statistics of a period and its preceding period
+	FinishPeriod(statistics models.GrowthStatistics, previous models.GrowthStatistics)
+	// RenderSection is called once the data of a section is available in the report
+	RenderSection(section models.Section, report models.Report)
+}
+
+// Analyze collects all metrics of the repository at options.RepositoryPath. Once ctx is done, the
+// running git commands are killed and Analyze returns the report with the sections collected so
+// far, marked as incomplete, together with the context's error.
+func Analyze(ctx context.Context, options Options) (*models.Report, error) {
+	clock := options.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	startTime := clock()
+	asOf := options.AsOf
+	if asOf.IsZero() {
+		asOf = startTime
+	}
+	interval := options.Interval
+	if interval == "" {
+		interval = models.YearInterval
+	}
+	forecastModel := options.ForecastModel
+	if forecastModel == "" {
+		forecastModel = models.LinearForecast
+	}
+	concernThresholds := options.ConcernThresholds
+	if concernThresholds == nil {
+		concernThresholds = models.ConcernProfiles[models.DefaultConcernProfile]
+	}
+	var since time.Time
+	if !options.Since.IsZero() {
+		local := options.Since.Local()
+		since = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repositoryPath := options.RepositoryPath
+	if repositoryPath == "" {
+		repositoryPath = "."
+	}
+	jobs := options.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	workers := utils.NewWorkerPool(jobs)
+	repository := git.Repository{Path: repositoryPath, Debug: options.Debug, Workers: workers, Runner: options.Runner, AsOf: options.AsOf, Refs: options.Refs}
+
+	gitDirectory, err := repository.GetGitDirectory(ctx)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	// Resolve the refs once, so all sections analyze the same commits even if refs are updated meanwhile.
+	// Repositories without any commits have no refs either and are reported as such below.
+	tips, err := repository.GetRefTips(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err == nil && len(tips) == 0 && !options.Refs.IsAll() {
+		return nil, fmt.Errorf("%w %s", ErrNoMatchingRefs, options.Refs)
+	}
+
+	analysis := &analysis{
+		ctx:          ctx,
+		clock:        clock,
+		asOf:         asOf,
+		since:        since,
+		interval:     interval,
+		historic:     !options.AsOf.IsZero(),
+		repository:   repository,
+		workers:      workers,
+		gitDirectory: gitDirectory,
+		tips:         tips,
+		cache:        options.Cache && options.AsOf.IsZero() && options.Refs.IsAll(),
+		observer:     options.Observer,
+		limits:       options.Limits.orDefaults(),
+		report:       models.Report{SchemaVersion: models.ReportSchemaVersion, Interval: interval, ForecastModel: forecastModel, ConcernThresholds: concernThresholds, Policy: options.Policy},
+	}
+	if analysis.observer == nil {
+		analysis.observer = noObserver{}
+	}
+
+	if options.Range != nil {
+		err = analysis.runStages(
+			stage{models.RunSection, func() { analysis.collectRunInformation(startTime) }},
+			stage{models.RangeSection, func() { analysis.collectRange(*options.Range) }},
+		)
+		if err == nil && analysis.rangeError != nil {
+			return nil, analysis.rangeError
+		}
+		if err == nil && options.Policy != nil {
+			err = analysis.runStages(stage{models.PolicySection, analysis.collectPolicy})
+		}
+	} else {
+		err = analysis.runStages(
+			stage{models.RunSection, func() { analysis.collectRunInformation(startTime) }},
+			stage{models.RepositorySection, analysis.collectRepositoryInformation},
+		)
+		if err == nil && analysis.report.Repository.FirstDate.IsZero() {
+			return nil, ErrNoCommits
+		}
+		if err == nil {
+			selected := options.Sections
+			if len(selected) == 0 {
+				selected = models.SelectableSections
+			}
+			collectors := map[models.Section]func(){
+				models.GrowthSection:              analysis.collectGrowth,
+				models.ConcernSection:             analysis.collectConcernProjections,
+				models.BacktestSection:            analysis.collectBacktest,
+				models.FileExtensionsSection:      analysis.collectFileExtensions,
+				models.FileExtensionGrowthSection: analysis.collectFileExtensionGrowth,
+				models.DirectoriesSection:         analysis.collectLargestDirectories,
+				models.FilesSection:               analysis.collectLargestFiles,
+				models.RateOfChangesSection:       analysis.collectRateOfChanges,
+				models.ContributorsSection:        analysis.collectContributors,
+			}
+			var stages []stage
+			for _, section := range selected {
+				if collect, exists := collectors[section]; exists && (section != models.BacktestSection || options.Backtest) {
+					stages = append(stages, stage{section, collect})
+					delete(collectors, section) // Each section is collected once
+				}
+			}
+			analysis.startCollectors(selected)
+			if options.Policy != nil {
+				stages = append(stages, stage{models.PolicySection, analysis.collectPolicy})
+			}
+			// The history walk covers only the parts the collected sections need. The sections that walk
+			// the objects also report the authors of the growth statistics and repository totals, which
+			// come from the contributors.
+			for _, stage := range stages {
+				switch stage.section {
+				case models.ContributorsSection:
+					analysis.historyParts.Contributors = true
+				case models.RateOfChangesSection:
+				default:
+					analysis.historyParts = git.AllHistoryParts
+				}
+			}
+			err = analysis.runStages(stages...)
+		}
+	}
+
+	var memoryStatistics runtime.MemStats
+	runtime.ReadMemStats(&memoryStatistics)
+	analysis.report.Run.Duration = clock().Sub(startTime)
+	analysis.report.Run.MemoryFootprint = memoryStatistics.Sys
+	analysis.report.Incomplete = err != nil
+
+	return &analysis.report, err
+}
+
+// stage collects the data of a single report section
+type stage struct {
+	section models.Section
+	collect func()
+}
+
+// runStages runs the stages in order and records the duration and peak memory of each stage.
+// It stops once the context is done and discards the data of a stage that was interrupted,
+// so the report only contains complete sections.
+func (analysis *analysis) runStages(stages ...stage) error {
+	for _, stage := range stages {
+		if err := analysis.ctx.Err(); err != nil {
+			return err
+		}
+		report := analysis.report
+		startTime := analysis.clock()
+		peakMemory := measurePeakMemory(stage.collect)
+		if analysis.interrupted {
+			analysis.report = report
+			return analysis.ctx.Err()
+		}
+		analysis.report.Run.Phases = append(analysis.report.Run.Phases, models.PhaseInformation{
+			Section:    stage.section,
+			Duration:   analysis.clock().Sub(startTime),
+			PeakMemory: peakMemory,
+		})
+	}
+	return nil
+}
+
+// analysis holds the state of a single Analyze call
+type analysis struct {
+	ctx         context.Context
+	interrupted bool // Set when the context was done before the current section was rendered
+	clock       func() time.Time
+	asOf        time.Time       // Time the repository is analyzed as of
+	historic    bool            // The repository is analyzed as of a time given in the options
+	since       time.Time       // Start of the month the window starts in, zero for the whole history
+	interval    models.Interval // Length of the periods statistics are grouped by
+	limits      Limits
+
+	repository   git.Repository
+	workers      *utils.WorkerPool
+	gitDirectory string
+	tips         []string // Objects the analyzed refs point to
+	cache        bool
+	observer     Observer
+	report       models.Report
+
+	historyParts     git.HistoryParts // Parts of the history the selected sections need
+	windowHistory    *git.History     // History from the start of the window on, the whole history without a window
+	periodStatistics map[models.Period]models.GrowthStatistics
+	baseline         models.GrowthStatistics // Totals before the window, zero without a window
+	windowStatistics models.GrowthStatistics // Totals of the objects introduced within the window
+
+	historyLoaded bool                      // The history was walked and the statistics below are set
+	growth        []models.GrowthStatistics // Statistics of the periods within the window in order
+	estimates     []models.GrowthStatistics // Estimated statistics of the following periods
+
+	rangeFiles []models.FileInformation // Files of the blobs the measured range adds, largest blob first
+	rangeError error                    // Set when the objects of the range could not be measured
+
+	// Collected in the background and waited for by the sections that need them, nil if none is selected
+	defaultBranch func() (string, map[string]bool, error)
+	rateOfChanges func() models.RateOfChangesReport
+}
+
+// startCollectors starts collecting the data of the selected sections that does not depend on the
+// history walk, so it can be collected by the other workers while the history is walked. Each
+// collector stores its results separately, which keeps the report identical to a sequential run.
+func (analysis *analysis) startCollectors(selected []models.Section) {
+	if slices.Contains(selected, models.DirectoriesSection) {
+		analysis.startDefaultBranchCollector()
+	}
+	if slices.Contains(selected, models.RateOfChangesSection) {
+		analysis.startRateOfChangesCollector()
+	}
+}
+
+// startDefaultBranchCollector starts collecting the files of the default branch
+func (analysis *analysis) startDefaultBranchCollector() {
+	var defaultBranch string
+	var defaultBranchFiles map[string]bool
+	var defaultBranchError error
+	waitForDefaultBranch := analysis.workers.Go(func() {
+		defaultBranch, defaultBranchError = analysis.repository.GetDefaultBranch(analysis.ctx)
+		if defaultBranchError == nil {
+			defaultBranchFiles, defaultBranchError = analysis.repository.GetBranchFiles(analysis.ctx, defaultBranch)
+		} else {
+			defaultBranch = ""
+		}
+	})
+	analysis.defaultBranch = func() (string, map[string]bool, error) {
+		waitForDefaultBranch()
+		return defaultBranch, defaultBranchFiles, defaultBranchError
+	}
+}
+
+// startRateOfChangesCollector starts collecting the rate of changes
+func (analysis *analysis) startRateOfChangesCollector() {
+	// Without limited refs, the rate of changes of the current branch is more telling than
+	// the one of all refs, which usually contain the same commits many times over
+	var rateOfChangesTips []string
+	if !analysis.repository.Refs.IsAll() {
+		rateOfChangesTips = analysis.tips
+	}
+	var rateOfChanges models.RateOfChangesReport
+	waitForRateOfChanges := analysis.workers.Go(func() {
+		if ratesByPeriod, branchName, err := analysis.repository.GetRateOfChanges(analysis.ctx, rateOfChangesTips, analysis.interval, analysis.since); err == nil {
+			rateOfChanges = sections.BuildRateOfChanges(ratesByPeriod, branchName)
+			if rateOfChangesTips != nil {
+				rateOfChanges.Refs = analysis.repository.Refs.String()
+			}
+		}
+	})
+	analysis.rateOfChanges = func() models.RateOfChangesReport {
+		waitForRateOfChanges()
+		return rateOfChanges
+	}
+}
+
+func (analysis *analysis) collectRunInformation(startTime time.Time) {
+	analysis.observer.StartSection(models.RunSection)
+	analysis.report.Run = sections.BuildRunInformation(startTime, analysis.repository.GetGitVersion(analysis.ctx))
+	analysis.renderSection(models.RunSection)
+}
+
+func (analysis *analysis) collectRepositoryInformation() {
+	analysis.observer.StartSection(models.RepositorySection)
+
+	// Get Git directory last modified time and most recent fetch, which do not apply to historic analyses
+	lastModified, mostRecentFetch := "", ""
+	if !analysis.historic {
+		lastModified = UnknownValue
+		if information, err := os.Stat(analysis.gitDirectory); err == nil {
+			lastModified = information.ModTime().Format("Mon, 02 Jan 2006 15:04 MST")
+		}
+		mostRecentFetch = git.GetLastFetchTime(analysis.gitDirectory)
+	}
+
+	// Remote URL - only set if there is one
+	remote := ""
+	if remoteOutput, err := analysis.repository.RunGitCommand(analysis.ctx, "remote", "get-url", "origin"); err == nil {
+		remote = strings.TrimSpace(string(remoteOutput))
+	}
+
+	// Most recent commit
+	lastCommit := UnknownValue
+	if lastHashOutput, err := analysis.runOnAnalyzedRefs("rev-list", "--max-count=1", "--abbrev-commit"); err == nil && len(lastHashOutput) > 0 {
+		lastHash := strings.TrimSpace(string(lastHashOutput))
+		if commandOutput, err := analysis.repository.RunGitCommand(analysis.ctx, "show", "-s", "--format=%cD", lastHash); err == nil {
+			lastDate, _ := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", strings.TrimSpace(string(commandOutput)))
+			lastCommit = fmt.Sprintf("%s (%s)", lastDate.Format("Mon, 02 Jan 2006"), lastHash)
+		}
+	}
+
+	// First commit and age
+	firstCommit := UnknownValue
+	ageString := UnknownValue
+	var firstCommitTime time.Time
+	if firstOutput, err := analysis.runOnAnalyzedRefs("rev-list", "--max-parents=0", "--format=%cD"); err == nil {
+		lines := strings.Split(strings.TrimSpace(string(firstOutput)), "\n")
+		type commit struct {
+			hash string
+			date time.Time
+		}
+		var commits []commit
+		for i := 0; i < len(lines); i += 2 {
+			if i+1 >= len(lines) {
+				break
+			}
+			hash := strings.TrimPrefix(lines[i], "commit ")[:6]
+			if date, err := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", strings.TrimSpace(lines[i+1])); err == nil {
+				commits = append(commits, commit{hash: hash, date: date})
+			}
+		}
+		if len(commits) > 0 {
+			sort.Slice(commits, func(i, j int) bool {
+				return commits[i].date.Before(commits[j].date)
+			})
+			first := commits[0]
+			firstCommitTime = first.date
+			firstCommit = fmt.Sprintf("%s (%s)", first.date.Format("Mon, 02 Jan 2006"), first.hash)
+			years, months, days := utils.CalculateYearsMonthsDays(first.date, analysis.asOf)
+			var parts []string
+			if years > 0 {
+				parts = append(parts, fmt.Sprintf("%d years", years))
+			}
+			if months > 0 {
+				parts = append(parts, fmt.Sprintf("%d months", months))
+			}
+			if days > 0 {
+				parts = append(parts, fmt.Sprintf("%d days", days))
+			}
+			ageString = strings.Join(parts, " ")
+		}
+	}
+
+	analysis.report.Repository = &models.RepositoryInformation{
+		GitDirectory:    analysis.gitDirectory,
+		Remote:          remote,
+		Refs:            analysis.repository.Refs.String(),
+		LastModified:    lastModified,
+		MostRecentFetch: mostRecentFetch,
+		LastCommit:      lastCommit,
+		FirstCommit:     firstCommit,
+		Age:             ageString,
+		FirstDate:       firstCommitTime,
+		AsOf:            analysis.asOf,
+		Since:           analysis.since,
+	}
+	analysis.renderSection(models.RepositorySection)
+}
+
+// runOnAnalyzedRefs runs a git command on the commits up to HEAD or, if the analyzed refs are
+// limited, on the commits up to the analyzed refs
+func (analysis *analysis) runOnAnalyzedRefs(args ...string) ([]byte, error) {
+	args = append(args, analysis.repository.CommitLimits()...)
+	if analysis.repository.Refs.IsAll() {
+		return analysis.repository.RunGitCommand(analysis.ctx, append(args, "HEAD")...)
+	}
+	return analysis.repository.RunGitCommandOnRevisions(analysis.ctx, analysis.tips, args...)
+}
+
+func (analysis *analysis) collectGrowth() {
+	analysis.observer.StartSection(models.GrowthSection)
+	analysis.loadHistory(true)
+	analysis.report.Growth = analysis.growth
+	analysis.report.Estimates = analysis.estimates
+	analysis.renderSection(models.GrowthSection)
+}
+
+// loadHistory walks the history once for all sections that need it and derives the statistics of
+// the periods within the window. With showPeriods, the observer is notified about each period as
+// the walk reaches it, which the growth section does if no other section walked the history before.
+func (analysis *analysis) loadHistory(showPeriods bool) {
+	if analysis.historyLoaded {
+		return
+	}
+	analysis.historyLoaded = true
+
+	// Walk all objects once, showing the running totals of each period within the window as the
+	// walk reaches later periods. The whole history is walked, so totals include earlier periods.
+	historyPeriod := analysis.interval.PeriodOf(analysis.report.Repository.FirstDate)
+	firstPeriod := historyPeriod
+	if !analysis.since.IsZero() && analysis.interval.PeriodOf(analysis.since).Index > firstPeriod.Index {
+		firstPeriod = analysis.interval.PeriodOf(analysis.since)
+	}
+	currentPeriod := analysis.interval.PeriodOf(analysis.asOf)
+	var previous, beforePrevious models.GrowthStatistics
+	progressPeriod := historyPeriod
+	observer := analysis.observer
+	if !showPeriods {
+		observer = noObserver{}
+	}
+	if progressPeriod == firstPeriod {
+		observer.StartPeriod(progressPeriod, previous, beforePrevious)
+	}
+	history, err := analysis.updateHistory(func(month models.Period, running models.GrowthStatistics) {
+		period := month.In(analysis.interval)
+		for ; progressPeriod.Index < min(period.Index, currentPeriod.Index); progressPeriod = progressPeriod.Add(1) {
+			running.Period = progressPeriod
+			if progressPeriod.Index >= firstPeriod.Index {
+				observer.FinishPeriod(running, previous)
+			}
+			beforePrevious, previous = previous, running
+			if progressPeriod.Index+1 >= firstPeriod.Index {
+				observer.StartPeriod(progressPeriod.Add(1), previous, beforePrevious)
+			}
+		}
+	})
+	periodStatistics := make(map[models.Period]models.GrowthStatistics)
+	var baseline models.GrowthStatistics
+	windowHistory := history
+	totalAuthors := 0
+	if err == nil {
+		periodStatistics = history.GrowthStatistics(firstPeriod, currentPeriod)
+
+		// Deltas of the first period within the window are relative to the totals before the window
+		if !analysis.since.IsZero() {
+			var before *git.History
+			sinceMonth := models.MonthInterval.PeriodOf(analysis.since)
+			before, windowHistory = history.Split(sinceMonth)
+			baseline = before.GrowthStatistics(sinceMonth, sinceMonth)[sinceMonth]
+			_, baseline.Authors = before.CumulativeUniqueAuthors(analysis.interval)
+			baseline.Period = firstPeriod.Add(-1)
+		}
+
+		// Inject cumulative unique authors into the statistics of each period, periods without
+		// commits keep the count of the preceding period
+		var cumulativeAuthors map[models.Period]int
+		cumulativeAuthors, totalAuthors = history.CumulativeUniqueAuthors(analysis.interval)
+		authorsCount := 0
+		for period := firstPeriod; period.Index <= currentPeriod.Index; period = period.Add(1) {
+			if count, ok := cumulativeAuthors[period]; ok {
+				authorsCount = count
+			}
+			stats := periodStatistics[period]
+			stats.Authors = authorsCount
+			periodStatistics[period] = stats
+		}
+	}
+	totalStatistics := periodStatistics[currentPeriod]
+
+	// Save repository totals (including authors)
+	repositoryInformation := analysis.report.Repository
+	repositoryInformation.TotalCommits = totalStatistics.Commits
+	repositoryInformation.TotalAuthors = totalAuthors
+	repositoryInformation.TotalTrees = totalStatistics.Trees
+	repositoryInformation.TotalBlobs = totalStatistics.Blobs
+	repositoryInformation.CompressedSize = totalStatistics.Compressed
+	repositoryInformation.UncompressedSize = totalStatistics.Uncompressed
+
+	calculateDerivedStatistics(periodStatistics, *repositoryInformation, baseline, firstPeriod, currentPeriod)
+
+	var periods []models.Period
+	for period := range periodStatistics {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Index < periods[j].Index })
+	for _, period := range periods {
+		analysis.growth = append(analysis.growth, periodStatistics[period])
+	}
+	analysis.estimates = sections.BuildGrowthEstimates(periodStatistics, *repositoryInformation, analysis.interval, sections.NewEstimator(analysis.report.ForecastModel), analysis.limits.ForecastPeriods)
+
+	analysis.windowHistory = windowHistory
+	analysis.periodStatistics = periodStatistics
+	analysis.baseline = baseline
+	analysis.windowStatistics = totalStatistics
+	if windowHistory != history {
+		analysis.windowStatistics = windowHistory.GrowthStatistics(currentPeriod, currentPeriod)[currentPeriod]
+	}
+}
+
+// calculateDerivedStatistics calculates and stores delta, percentage and delta percentage values of
+// each period. The deltas of the first period are relative to the baseline, the totals before it.
+func calculateDerivedStatistics(periodStatistics map[models.Period]models.GrowthStatistics, repositoryInformation models.RepositoryInformation, baseline models.GrowthStatistics, firstPeriod, currentPeriod models.Period) {
+	previousCumulative := baseline
+	var previousDelta models.GrowthStatistics
+
+	for period := firstPeriod; period.Index <= currentPeriod.Index; period = period.Add(1) {
+		cumulative, ok := periodStatistics[period]
+		if !ok {
+			continue
+		}
+
+		// Calculate delta values (period-over-period changes)
+		cumulative.AuthorsDelta = cumulative.Authors - previousCumulative.Authors
+		cumulative.CommitsDelta = cumulative.Commits - previousCumulative.Commits
+		cumulative.TreesDelta = cumulative.Trees - previousCumulative.Trees
+		cumulative.BlobsDelta = cumulative.Blobs - previousCumulative.Blobs
+		cumulative.CompressedDelta = cumulative.Compressed - previousCumulative.Compressed
+		cumulative.UncompressedDelta = cumulative.Uncompressed - previousCumulative.Uncompressed
+
+		// Calculate percentage of total
+		if repositoryInformation.TotalAuthors > 0 {
+			cumulative.AuthorsPercent = float64(cumulative.AuthorsDelta) / float64(repositoryInformation.TotalAuthors) * 100
+		}
+		if repositoryInformation.TotalCommits > 0 {
+			cumulative.CommitsPercent = float64(cumulative.CommitsDelta) / float64(repositoryInformation.TotalCommits) * 100
+		}
+		if repositoryInformation.TotalTrees > 0 {
+			cumulative.TreesPercent = float64(cumulative.TreesDelta) / float64(repositoryInformation.TotalTrees) * 100
+		}
+		if repositoryInformation.TotalBlobs > 0 {
+			cumulative.BlobsPercent = float64(cumulative.BlobsDelta) / float64(repositoryInformation.TotalBlobs) * 100
+		}
+		if repositoryInformation.CompressedSize > 0 {
+			cumulative.CompressedPercent = float64(cumulative.CompressedDelta) / float64(repositoryInformation.CompressedSize) * 100
+		}
+		if repositoryInformation.UncompressedSize > 0 {
+			cumulative.UncompressedPercent = float64(cumulative.UncompressedDelta) / float64(repositoryInformation.UncompressedSize) * 100
+		}
+
+		// Calculate delta percentage changes (Δ%)
+		if previousDelta.Period != (models.Period{}) { // Skip first period
+			if previousDelta.AuthorsDelta > 0 {
+				cumulative.AuthorsDeltaPercent = float64(cumulative.AuthorsDelta-previousDelta.AuthorsDelta) / float64(previousDelta.AuthorsDelta) * 100
+			}
+			if previousDelta.CommitsDelta > 0 {
+				cumulative.CommitsDeltaPercent = float64(cumulative.CommitsDelta-previousDelta.CommitsDelta) / float64(previousDelta.CommitsDelta) * 100
+			}
+			if previousDelta.TreesDelta > 0 {
+				cumulative.TreesDeltaPercent = float64(cumulative.TreesDelta-previousDelta.TreesDelta) / float64(previousDelta.TreesDelta) * 100
+			}
+			if previousDelta.BlobsDelta > 0 {
+				cumulative.BlobsDeltaPercent = float64(cumulative.BlobsDelta-previousDelta.BlobsDelta) / float64(previousDelta.BlobsDelta) * 100
+			}
+			if previousDelta.CompressedDelta > 0 {
+				cumulative.CompressedDeltaPercent = float64(cumulative.CompressedDelta-previousDelta.CompressedDelta) / float64(previousDelta.CompressedDelta) * 100
+			}
+			if previousDelta.UncompressedDelta > 0 {
+				cumulative.UncompressedDeltaPercent = float64(cumulative.UncompressedDelta-previousDelta.UncompressedDelta) / float64(previousDelta.UncompressedDelta) * 100
+			}
+		}
+
+		// Store the updated statistics back in the map
+		periodStatistics[period] = cumulative
+
+		// Update for next iteration
+		previousCumulative = cumulative
+		previousDelta = cumulative
+	}
+}
+
+// updateHistory walks the parts of the history the selected sections need from the analyzed refs.
+// With the cache enabled, only the commits and objects added since the cached history are walked,
+// unless the cached history is no longer reachable from the refs, for example after a force push.
+func (analysis *analysis) updateHistory(progress func(month models.Period, running models.GrowthStatistics)) (*git.History, error) {
+	tips := analysis.tips
+
+	history := git.NewHistory()
+	if analysis.cache {
+		history.ObjectNames = git.NewObjectSet()
+		if cached, err := cache.Load(analysis.gitDirectory); err != nil {
+			utils.DebugPrint(analysis.repository.Debug, "Not using cache: %v", err)
+		} else if contained, err := analysis.repository.ContainsHistory(analysis.ctx, tips, cached); err != nil || !contained {
+			utils.DebugPrint(analysis.repository.Debug, "Not using cache: the cached history is no longer reachable from the refs")
+		} else {
+			history = cached
+		}
+	}
+	if history.Walked(tips, analysis.historyParts) {
+		return history, nil
+	}
+
+	if err := analysis.repository.UpdateHistory(analysis.ctx, history, tips, analysis.historyParts, progress); err != nil {
+		return nil, err
+	}
+	if analysis.cache {
+		if err := cache.Save(analysis.gitDirectory, history); err != nil {
+			utils.DebugPrint(analysis.repository.Debug, "Could not save cache: %v", err)
+		}
+	}
+	return history, nil
+}
+
+func (analysis *analysis) collectConcernProjections() {
+	analysis.observer.StartSection(models.ConcernSection)
+	analysis.loadHistory(false)
+	// The projections are based on the estimates, which are reported with them without the growth section
+	analysis.report.Estimates = analysis.estimates
+	analysis.report.ConcernProjections = analysis.concernProjections()
+	analysis.renderSection(models.ConcernSection)
+}
+
+// concernProjections projects when the metrics reach the levels of concern
+func (analysis *analysis) concernProjections() []models.ConcernProjection {
+	totals := analysis.periodStatistics[analysis.interval.PeriodOf(analysis.asOf)]
+	return sections.BuildConcernProjections(*analysis.report.Repository, totals.LargestFiles, analysis.estimates, analysis.report.ConcernThresholds)
+}
+
+func (analysis *analysis) collectBacktest() {
+	analysis.observer.StartSection(models.BacktestSection)
+	analysis.loadHistory(false)
+	analysis.report.Backtest = sections.BuildBacktest(analysis.periodStatistics, *analysis.report.Repository, analysis.interval)
+	analysis.renderSection(models.BacktestSection)
+}
+
+func (analysis *analysis) collectFileExtensions() {
+	analysis.observer.StartSection(models.FileExtensionsSection)
+	analysis.loadHistory(false)
+	analysis.report.LargestFileExtensions = sections.BuildFileExtensions(analysis.windowStatistics.LargestFiles, analysis.limits.FileExtensions)
+	analysis.renderSection(models.FileExtensionsSection)
+}
+
+func (analysis *analysis) collectFileExtensionGrowth() {
+	analysis.observer.StartSection(models.FileExtensionGrowthSection)
+	analysis.loadHistory(false)
+	periodStatistics := analysis.periodStatistics
+	if analysis.baseline.Commits > 0 {
+		// Compare the first period within the window with the files before the window
+		periodStatistics = maps.Clone(periodStatistics)
+		periodStatistics[analysis.baseline.Period] = analysis.baseline
+	}
+	analysis.report.FileExtensionGrowth = sections.BuildFileExtensionGrowth(periodStatistics, analysis.limits.GrowingExtensions)
+	analysis.renderSection(models.FileExtensionGrowthSection)
+}
+
+func (analysis *analysis) collectLargestDirectories() {
+	analysis.observer.StartSection(models.DirectoriesSection)
+	analysis.loadHistory(false)
+
+	// Compare against the default branch to mark moved, renamed or removed paths
+	defaultBranch, defaultBranchFiles, defaultBranchError := analysis.defaultBranch()
+
+	analysis.report.LargestDirectories = sections.BuildLargestDirectories(analysis.windowStatistics.LargestFiles, analysis.windowStatistics.Blobs, defaultBranch, defaultBranchFiles, defaultBranchError, analysis.limits.DirectoryDepth, analysis.limits.DirectoryThreshold)
+	analysis.renderSection(models.DirectoriesSection)
+}
+
+func (analysis *analysis) collectLargestFiles() {
+	analysis.observer.StartSection(models.FilesSection)
+	analysis.loadHistory(false)
+	analysis.report.LargestFiles = sections.BuildLargestFiles(analysis.windowStatistics.LargestFiles, analysis.windowStatistics.Blobs, analysis.limits.Files)
+	analysis.renderSection(models.FilesSection)
+}
+
+func (analysis *analysis) collectRateOfChanges() {
+	analysis.observer.StartSection(models.RateOfChangesSection)
+	analysis.report.RateOfChanges = analysis.rateOfChanges()
+	analysis.renderSection(models.RateOfChangesSection)
+}
+
+func (analysis *analysis) collectContributors() {
+	analysis.observer.StartSection(models.ContributorsSection)
+	analysis.loadHistory(false)
+	if analysis.windowHistory != nil {
+		topAuthorsByPeriod, totalAuthorsByPeriod, totalCommitsByPeriod, topCommittersByPeriod, totalCommittersByPeriod, allTimeAuthors, allTimeCommitters := analysis.windowHistory.TopCommitAuthors(analysis.limits.Contributors, analysis.interval)
+		analysis.report.Authors = sections.BuildContributors(topAuthorsByPeriod, totalAuthorsByPeriod, totalCommitsByPeriod, allTimeAuthors, analysis.limits.Contributors)
+		analysis.report.Committers = sections.BuildContributors(topCommittersByPeriod, totalCommittersByPeriod, totalCommitsByPeriod, allTimeCommitters, analysis.limits.Contributors)
+	}
+	analysis.renderSection(models.ContributorsSection)
+}
+
+func (analysis *analysis) collectRange(objects git.Range) {
+	analysis.observer.StartSection(models.RangeSection)
+	statistics, err := analysis.repository.MeasureRange(analysis.ctx, objects)
+	if err != nil {
+		analysis.rangeError = err
+		return
+	}
+	analysis.rangeFiles = statistics.LargestFiles
+	report := sections.BuildRangeReport(objects.Names, statistics, analysis.limits.Files)
+	analysis.report.Range = &report
+	analysis.renderSection(models.RangeSection)
+}
+
+func (analysis *analysis) collectPolicy() {
+	analysis.observer.StartSection(models.PolicySection)
+	if analysis.report.Range != nil {
+		analysis.report.Violations = sections.EvaluateRangePolicy(*analysis.report.Policy, *analysis.report.Range, analysis.rangeFiles, analysis.report.ConcernThresholds)
+	} else {
+		// The limits are checked against the history even if the sections showing it are not selected
+		analysis.loadHistory(false)
+		report := analysis.report
+		report.Growth = analysis.growth
+		report.ConcernProjections = analysis.concernProjections()
+		analysis.report.Violations = sections.EvaluatePolicy(*analysis.report.Policy, report, analysis.windowStatistics.LargestFiles)
+	}
+	analysis.renderSection(models.PolicySection)
+}
+
+// renderSection notifies the observer that the data of the section is available, unless the
+// context was done while it was collected and the data may be incomplete
+func (analysis *analysis) renderSection(section models.Section) {
+	if analysis.ctx.Err() != nil {
+		analysis.interrupted = true
+		return
+	}
+	analysis.observer.RenderSection(section, analysis.report)
+}
+
+// noObserver ignores all progress notifications
+type noObserver struct{}
+
+func (noObserver) StartSection(models.Section)                                                 {}
+func (noObserver) StartPeriod(models.Period, models.GrowthStatistics, models.GrowthStatistics) {}
+func (noObserver) FinishPeriod(models.GrowthStatistics, models.GrowthStatistics)               {}
+func (noObserver) RenderSection(models.Section, models.Report)                                 {}
@@ -0,0 +1,618 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"git-metrics/pkg/cache"
+	"git-metrics/pkg/git"
+	"git-metrics/pkg/models"
+)
+
+// createRepository initializes a git repository in a temporary directory
+func createRepository(t *testing.T) string {
+	t.Helper()
+	path := t.TempDir()
+	runGit(t, path, "init", "--quiet")
+	return path
+}
+
+// runGit runs a git command in the given directory with a fixed identity and date
+func runGit(t *testing.T, path string, args ...string) {
+	t.Helper()
+	runGitAt(t, path, "2024-03-01T12:00:00Z", args...)
+}
+
+// runGitAt runs a git command in the given directory with a fixed identity and the given date
+func runGitAt(t *testing.T, path, date string, args ...string) {
+	t.Helper()
+	command := exec.Command("git", args...)
+	command.Dir = path
+	command.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com",
+		"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com",
+		"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("# Example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	workingDirectory, _ := os.Getwd()
+	report, err := Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if currentDirectory, _ := os.Getwd(); currentDirectory != workingDirectory {
+		t.Errorf("Analyze() changed the working directory to %s", currentDirectory)
+	}
+	if report.SchemaVersion != models.ReportSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", models.ReportSchemaVersion, report.SchemaVersion)
+	}
+	if report.Interval != models.YearInterval || report.ForecastModel != models.LinearForecast {
+		t.Errorf("expected yearly periods and linear estimates by default, got %q and %q", report.Interval, report.ForecastModel)
+	}
+	if report.Repository.TotalCommits != 1 || report.Repository.TotalBlobs != 1 || report.Repository.TotalAuthors != 1 {
+		t.Errorf("expected 1 commit, blob and author, got %+v", report.Repository)
+	}
+	if len(report.Growth) == 0 || report.Growth[0].Period.String() != "2024" {
+		t.Errorf("expected growth to start in 2024, got %+v", report.Growth)
+	}
+	if len(report.LargestFiles.Files) != 1 || report.LargestFiles.Files[0].Path != "README.md" {
+		t.Errorf("expected README.md as largest file, got %+v", report.LargestFiles.Files)
+	}
+	if len(report.Authors.AllTime) != 1 || report.Authors.AllTime[0].Name != "Jane Doe" {
+		t.Errorf("expected Jane Doe as only author, got %+v", report.Authors.AllTime)
+	}
+	if len(report.Run.Phases) != 10 || report.Run.Phases[2].Section != models.GrowthSection || report.Run.Phases[2].PeakMemory == 0 {
+		t.Errorf("expected the peak memory of all ten sections, got %+v", report.Run.Phases)
+	}
+}
+
+func TestAnalyzeTwiceInOneProcess(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("# Example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	// Objects counted by one analysis must not be skipped by the next one
+	first, err := Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil {
+		t.Fatalf("first Analyze() error = %v", err)
+	}
+	second, err := Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil {
+		t.Fatalf("second Analyze() error = %v", err)
+	}
+	if first.Repository.TotalBlobs != 1 || second.Repository.TotalBlobs != first.Repository.TotalBlobs ||
+		second.Repository.TotalTrees != first.Repository.TotalTrees || second.Repository.CompressedSize != first.Repository.CompressedSize {
+		t.Errorf("expected identical totals, got %+v and %+v", first.Repository, second.Repository)
+	}
+}
+
+func TestAnalyzeWithCache(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("# Example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("# Changed example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "commit", "--quiet", "--all", "-m", "Change README")
+	if _, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true}); err != nil {
+		t.Fatalf("first Analyze() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git", cache.FileName)); err != nil {
+		t.Fatalf("expected the cache to be written: %v", err)
+	}
+
+	// Adding the content of the first commit again must not count it twice, although it is not
+	// part of the commit the cache was written for
+	if err := os.WriteFile(filepath.Join(path, "COPY.md"), []byte("# Example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "COPY.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Copy README")
+
+	cached, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true})
+	if err != nil {
+		t.Fatalf("second Analyze() error = %v", err)
+	}
+	complete, err := Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil {
+		t.Fatalf("Analyze() without cache error = %v", err)
+	}
+	if cached.Repository.TotalCommits != 3 || cached.Repository.TotalBlobs != complete.Repository.TotalBlobs ||
+		cached.Repository.TotalTrees != complete.Repository.TotalTrees || cached.Repository.CompressedSize != complete.Repository.CompressedSize {
+		t.Errorf("expected cached totals %+v to match %+v", cached.Repository, complete.Repository)
+	}
+	if cached.Authors.AllTimeCommits != 3 {
+		t.Errorf("expected 3 commits of all authors, got %d", cached.Authors.AllTimeCommits)
+	}
+}
+
+func TestAnalyzeWithCacheAfterRename(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "big.dat"), []byte(strings.Repeat("data", 1000)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "big.dat")
+	runGit(t, path, "commit", "--quiet", "-m", "Add data")
+	if _, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true}); err != nil {
+		t.Fatalf("first Analyze() error = %v", err)
+	}
+
+	// The renamed blob is not listed by the walk of the added commits, but must not keep its old path
+	runGit(t, path, "mv", "big.dat", "moved.dat")
+	runGit(t, path, "commit", "--quiet", "-m", "Move data")
+	cached, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true})
+	if err != nil {
+		t.Fatalf("second Analyze() error = %v", err)
+	}
+	complete, err := Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil {
+		t.Fatalf("Analyze() without cache error = %v", err)
+	}
+	paths := func(report *models.Report) []string {
+		var paths []string
+		for _, file := range report.LargestFiles.Files {
+			paths = append(paths, file.Path)
+		}
+		return paths
+	}
+	if !slices.Equal(paths(cached), []string{"moved.dat"}) || !slices.Equal(paths(cached), paths(complete)) {
+		t.Errorf("expected the cached largest files %v to match %v", paths(cached), paths(complete))
+	}
+	if cached.Repository.TotalBlobs != complete.Repository.TotalBlobs || cached.Repository.TotalCommits != 2 {
+		t.Errorf("expected cached totals %+v to match %+v", cached.Repository, complete.Repository)
+	}
+
+	// Commits that add only new blobs still walk only what they added
+	if err := os.WriteFile(filepath.Join(path, "moved.dat"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "commit", "--quiet", "--all", "-m", "Change data")
+	runner := &git.RecordingRunner{}
+	if _, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true, Runner: runner}); err != nil {
+		t.Fatalf("third Analyze() error = %v", err)
+	}
+	for _, recording := range runner.Recordings() {
+		if slices.Contains(recording.Args, "--objects") && !strings.Contains(recording.Stdin, "^") {
+			t.Errorf("expected only the added commit to be walked, got git %s with %q", strings.Join(recording.Args, " "), recording.Stdin)
+		}
+	}
+}
+
+func TestAnalyzeIsIndependentOfJobs(t *testing.T) {
+	path := createRepository(t)
+	for _, name := range []string{"README.md", "docs/guide.md", "main.go"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(path, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, path, "add", name)
+		runGit(t, path, "commit", "--quiet", "-m", "Add "+name)
+	}
+
+	clock := func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+	sequential, err := Analyze(context.Background(), Options{RepositoryPath: path, Jobs: 1, Clock: clock})
+	if err != nil {
+		t.Fatalf("Analyze() with 1 job error = %v", err)
+	}
+	concurrent, err := Analyze(context.Background(), Options{RepositoryPath: path, Jobs: 4, Clock: clock})
+	if err != nil {
+		t.Fatalf("Analyze() with 4 jobs error = %v", err)
+	}
+
+	// Everything written to the report except the run information must be identical
+	sequential.Run, concurrent.Run = models.RunInformation{}, models.RunInformation{}
+	sequentialJSON, _ := json.Marshal(sequential)
+	concurrentJSON, _ := json.Marshal(concurrent)
+	if string(sequentialJSON) != string(concurrentJSON) {
+		t.Errorf("expected identical reports, got\n%s\nand\n%s", sequentialJSON, concurrentJSON)
+	}
+}
+
+func TestAnalyzeAsOf(t *testing.T) {
+	path := createRepository(t)
+	for index, date := range []string{"2021-06-01T12:00:00Z", "2022-06-01T12:00:00Z", "2023-06-01T12:00:00Z", "2024-06-01T12:00:00Z"} {
+		if err := os.WriteFile(filepath.Join(path, "README.md"), []byte(date), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitAt(t, path, date, "add", "README.md")
+		runGitAt(t, path, date, "commit", "--quiet", "-m", fmt.Sprintf("Change %d", index))
+	}
+
+	asOf := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		Cache:          true,
+		AsOf:           asOf,
+		Clock:          func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) },
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// Only the commits up to the date count and the current year is the date's year
+	if report.Repository.TotalCommits != 3 || report.Repository.TotalBlobs != 3 {
+		t.Errorf("expected 3 commits and blobs up to %v, got %+v", asOf, report.Repository)
+	}
+	if last := report.Growth[len(report.Growth)-1]; last.Period.String() != "2023" || last.Commits != 3 {
+		t.Errorf("expected growth up to 2023, got %+v", report.Growth)
+	}
+	if len(report.Estimates) == 0 || report.Estimates[0].Period.String() != "2023" {
+		t.Errorf("expected estimates starting in 2023, got %+v", report.Estimates)
+	}
+	if report.Repository.Age != "2 years 3 months 29 days" || !strings.HasPrefix(report.Repository.LastCommit, "Thu, 01 Jun 2023") {
+		t.Errorf("expected the age and most recent commit as of %v, got %q and %q", asOf, report.Repository.Age, report.Repository.LastCommit)
+	}
+	if report.Repository.LastModified != "" || !report.Repository.AsOf.Equal(asOf) || !report.Run.StartTime.Equal(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the time of the analysis and the run from the options, got %+v and %v", report.Repository, report.Run.StartTime)
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git", cache.FileName)); !os.IsNotExist(err) {
+		t.Errorf("expected no cache for a historic analysis, got %v", err)
+	}
+}
+
+func TestAnalyzeSince(t *testing.T) {
+	path := createRepository(t)
+	for index, date := range []string{"2021-06-01T12:00:00Z", "2022-06-01T12:00:00Z", "2023-06-01T12:00:00Z", "2024-06-01T12:00:00Z"} {
+		if err := os.WriteFile(filepath.Join(path, "README.md"), []byte(date), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitAt(t, path, date, "add", "README.md")
+		runGitAt(t, path, date, "commit", "--quiet", "-m", fmt.Sprintf("Change %d", index))
+	}
+
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		Since:          time.Date(2023, 1, 15, 0, 0, 0, 0, time.Local),
+		AsOf:           time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// Rows start with the window while totals and deltas include the earlier history
+	if report.Repository.TotalCommits != 4 || !report.Repository.Since.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected 4 commits in total and the window to start in January 2023, got %+v", report.Repository)
+	}
+	if len(report.Growth) != 2 || report.Growth[0].Period.String() != "2023" || report.Growth[0].Commits != 3 || report.Growth[0].CommitsDelta != 1 {
+		t.Errorf("expected growth of 1 commit in 2023 on top of 2 earlier ones, got %+v", report.Growth)
+	}
+	if report.LargestFiles.TotalBlobs != 2 || len(report.LargestFiles.Files) != 1 || report.LargestFiles.Files[0].Blobs != 2 {
+		t.Errorf("expected the 2 blobs added within the window, got %+v", report.LargestFiles)
+	}
+	if report.Authors.AllTimeCommits != 2 || len(report.Authors.Periods) != 2 {
+		t.Errorf("expected the 2 commits within the window, got %+v", report.Authors)
+	}
+	if len(report.RateOfChanges.Periods) != 2 || report.RateOfChanges.Periods[0].Period.String() != "2023" {
+		t.Errorf("expected the rates of 2023 and 2024, got %+v", report.RateOfChanges.Periods)
+	}
+}
+
+func TestAnalyzeInterval(t *testing.T) {
+	path := createRepository(t)
+	for index, date := range []string{"2024-01-15T12:00:00Z", "2024-05-15T12:00:00Z", "2024-08-15T12:00:00Z"} {
+		if err := os.WriteFile(filepath.Join(path, "README.md"), []byte(date), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitAt(t, path, date, "add", "README.md")
+		runGitAt(t, path, date, "commit", "--quiet", "-m", fmt.Sprintf("Change %d", index))
+	}
+
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		AsOf:           time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+		Interval:       models.QuarterInterval,
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if report.Interval != models.QuarterInterval {
+		t.Errorf("expected a quarterly report, got %q", report.Interval)
+	}
+	var growth []string
+	for _, statistics := range report.Growth {
+		growth = append(growth, fmt.Sprintf("%s:%d", statistics.Period, statistics.CommitsDelta))
+	}
+	if strings.Join(growth, " ") != "2024-Q1:1 2024-Q2:1 2024-Q3:1" {
+		t.Errorf("expected one commit in each quarter, got %v", growth)
+	}
+	if len(report.Estimates) == 0 || report.Estimates[0].Period.String() != "2024-Q3" {
+		t.Errorf("expected estimates starting in 2024-Q3, got %+v", report.Estimates)
+	}
+	if len(report.Authors.Periods) != 3 || report.Authors.Periods[2].Period.String() != "2024-Q3" {
+		t.Errorf("expected the authors of three quarters, got %+v", report.Authors.Periods)
+	}
+	if len(report.FileExtensionGrowth) == 0 || report.FileExtensionGrowth[0].Period.Interval != models.QuarterInterval {
+		t.Errorf("expected extension growth per quarter, got %+v", report.FileExtensionGrowth)
+	}
+
+	// Months without commits keep the authors of the preceding month
+	report, err = Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		AsOf:           time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+		Interval:       models.MonthInterval,
+		Backtest:       true,
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(report.Growth) < 2 || report.Growth[1].Period.String() != "2024-02" || report.Growth[1].Authors != 1 || report.Growth[1].AuthorsDelta != 0 {
+		t.Errorf("expected 1 author and no new ones in 2024-02, got %+v", report.Growth)
+	}
+	if report.Backtest == nil || report.Backtest.First.String() != "2024-03" || report.Backtest.Last.String() != "2024-07" {
+		t.Errorf("expected forecasts made from 2024-03 to 2024-07, got %+v", report.Backtest)
+	}
+	if len(report.Run.Phases) < 5 || report.Run.Phases[4].Section != models.BacktestSection {
+		t.Errorf("expected the backtest to follow the concern level projections, got %+v", report.Run.Phases)
+	}
+}
+
+func TestAnalyzePolicy(t *testing.T) {
+	path := createRepository(t)
+	for _, file := range []string{"README.md", "backup.zip"} {
+		if err := os.WriteFile(filepath.Join(path, file), []byte("content of "+file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, path, "add", ".")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		Policy:         &models.Policy{MaxDiskSize: 1 << 40, ForbiddenExtensions: []string{".zip"}},
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].Rule != models.ForbiddenExtensionRule {
+		t.Errorf("expected the forbidden extension as only violation, got %+v", report.Violations)
+	}
+	if phases := report.Run.Phases; len(phases) != 11 || phases[10].Section != models.PolicySection {
+		t.Errorf("expected the policy to be checked last, got %+v", phases)
+	}
+}
+
+func TestAnalyzeSections(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("readme"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	runner := &git.RecordingRunner{}
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		Runner:         runner,
+		Sections:       []models.Section{models.FilesSection, models.GrowthSection},
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	var sections []models.Section
+	for _, phase := range report.Run.Phases {
+		sections = append(sections, phase.Section)
+	}
+	expected := []models.Section{models.RunSection, models.RepositorySection, models.FilesSection, models.GrowthSection}
+	if !slices.Equal(sections, expected) {
+		t.Errorf("expected the sections %v, got %v", expected, sections)
+	}
+	if len(report.LargestFiles.Files) != 1 || len(report.Growth) == 0 || report.Authors.Periods != nil {
+		t.Errorf("expected only the files and the growth, got %+v", report)
+	}
+	// The rate of changes reads the log, which is not needed without its section
+	for _, recording := range runner.Recordings() {
+		if slices.Contains(recording.Args, "--format=%ct|%P|%an") {
+			t.Errorf("unexpected git %s", strings.Join(recording.Args, " "))
+		}
+	}
+}
+
+func TestAnalyzeSectionsWalkOnlyWhatTheyNeed(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("readme"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	isObjectWalk := func(args []string) bool {
+		return slices.Contains(args, "cat-file") || slices.Contains(args, "--objects")
+	}
+	isContributorsWalk := func(args []string) bool { return slices.Contains(args, "--format=%an%x00%cn%x00%cd") }
+	tests := []struct {
+		name       string
+		sections   []models.Section
+		unexpected func(args []string) bool
+	}{
+		{name: "contributors only", sections: []models.Section{models.ContributorsSection}, unexpected: isObjectWalk},
+		{name: "rates only", sections: []models.Section{models.RateOfChangesSection}, unexpected: func(args []string) bool {
+			return isObjectWalk(args) || isContributorsWalk(args)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := &git.RecordingRunner{}
+			if _, err := Analyze(context.Background(), Options{RepositoryPath: path, Runner: runner, Sections: test.sections}); err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			for _, recording := range runner.Recordings() {
+				if test.unexpected(recording.Args) {
+					t.Errorf("unexpected git %s", strings.Join(recording.Args, " "))
+				}
+			}
+		})
+	}
+
+	// The growth statistics and repository totals include the authors without the contributors section
+	report, err := Analyze(context.Background(), Options{RepositoryPath: path, Sections: []models.Section{models.GrowthSection}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if report.Repository.TotalAuthors != 1 || len(report.Growth) == 0 || report.Growth[len(report.Growth)-1].Authors != 1 {
+		t.Errorf("expected 1 author in the growth statistics and repository totals, got %d", report.Repository.TotalAuthors)
+	}
+}
+
+func TestAnalyzeRange(t *testing.T) {
+	path := createRepository(t)
+	for _, file := range []string{"README.md", "video.mp4"} {
+		if err := os.WriteFile(filepath.Join(path, file), []byte("content of "+file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, path, "add", file)
+		runGit(t, path, "commit", "--quiet", "-m", "Add "+file)
+	}
+
+	objects, err := git.ParseRange("HEAD~1..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Analyze(context.Background(), Options{
+		RepositoryPath: path,
+		Range:          &objects,
+		Policy:         &models.Policy{MaxBlobSize: 5},
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if report.Range == nil || report.Range.Commits != 1 || report.Range.Blobs != 1 || len(report.Range.LargestBlobs) != 1 || report.Range.LargestBlobs[0].Path != "video.mp4" {
+		t.Errorf("expected the commit adding video.mp4, got %+v", report.Range)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].Rule != models.MaxBlobSizeRule {
+		t.Errorf("expected video.mp4 to exceed the blob size, got %+v", report.Violations)
+	}
+	// Only the range is measured, not the history
+	if phases := report.Run.Phases; len(phases) != 3 || phases[1].Section != models.RangeSection || report.Repository != nil {
+		t.Errorf("expected the run, range and policy sections only, got %+v", phases)
+	}
+}
+
+func TestAnalyzeRefs(t *testing.T) {
+	path := createRepository(t)
+	for index, date := range []string{"2021-06-01T12:00:00Z", "2022-06-01T12:00:00Z"} {
+		if err := os.WriteFile(filepath.Join(path, "README.md"), []byte(date), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		runGitAt(t, path, date, "add", "README.md")
+		runGitAt(t, path, date, "commit", "--quiet", "-m", fmt.Sprintf("Change %d", index))
+	}
+	// Keep the second commit only reachable from a pull request ref
+	runGit(t, path, "update-ref", "refs/pull/1/head", "HEAD")
+	runGit(t, path, "reset", "--quiet", "--hard", "HEAD~1")
+
+	refs, err := git.ParseRefScope([]string{"^refs/pull/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Analyze(context.Background(), Options{RepositoryPath: path, Cache: true, Refs: refs})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if report.Repository.TotalCommits != 1 || report.Repository.Refs != "all refs excluding refs/pull/*" {
+		t.Errorf("expected 1 commit outside of the pull request refs, got %+v", report.Repository)
+	}
+	if !strings.HasPrefix(report.Repository.LastCommit, "Tue, 01 Jun 2021") || report.RateOfChanges.Refs != report.Repository.Refs {
+		t.Errorf("expected all sections to leave out the pull request refs, got %q and %+v", report.Repository.LastCommit, report.RateOfChanges)
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git", cache.FileName)); !os.IsNotExist(err) {
+		t.Errorf("expected no cache for limited refs, got %v", err)
+	}
+
+	report, err = Analyze(context.Background(), Options{RepositoryPath: path})
+	if err != nil || report.Repository.TotalCommits != 2 || report.Repository.Refs != "all refs" {
+		t.Errorf("expected 2 commits in all refs, got %+v, %v", report, err)
+	}
+
+	refs, _ = git.ParseRefScope([]string{"refs/tags/*"})
+	if _, err := Analyze(context.Background(), Options{RepositoryPath: path, Refs: refs}); !errors.Is(err, ErrNoMatchingRefs) {
+		t.Errorf("expected ErrNoMatchingRefs, got %v", err)
+	}
+}
+
+func TestAnalyzeWithoutCommits(t *testing.T) {
+	path := createRepository(t)
+
+	if _, err := Analyze(context.Background(), Options{RepositoryPath: path}); !errors.Is(err, ErrNoCommits) {
+		t.Errorf("expected ErrNoCommits, got %v", err)
+	}
+}
+
+func TestAnalyzeCanceled(t *testing.T) {
+	path := createRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Analyze(ctx, Options{RepositoryPath: path}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// cancelingObserver cancels the analysis when the collection of a section starts
+type cancelingObserver struct {
+	noObserver
+	section models.Section
+	cancel  context.CancelFunc
+}
+
+func (observer cancelingObserver) StartSection(section models.Section) {
+	if section == observer.section {
+		observer.cancel()
+	}
+}
+
+func TestAnalyzeCanceledReturnsPartialReport(t *testing.T) {
+	path := createRepository(t)
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("# Example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, path, "add", "README.md")
+	runGit(t, path, "commit", "--quiet", "-m", "Initial commit")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	report, err := Analyze(ctx, Options{
+		RepositoryPath: path,
+		Observer:       cancelingObserver{section: models.GrowthSection, cancel: cancel},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if report == nil || !report.Incomplete {
+		t.Fatalf("expected an incomplete report, got %+v", report)
+	}
+	if report.Repository.FirstDate.IsZero() {
+		t.Errorf("expected the repository section collected before canceling, got %+v", report.Repository)
+	}
+	if len(report.Growth) != 0 || len(report.Run.Phases) != 2 {
+		t.Errorf("expected no data of the canceled growth section, got %+v and %+v", report.Growth, report.Run.Phases)
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"git-metrics/pkg/utils"
+)
+
+// ErrInvalidConcernProfile is returned for a profile name other than those in ConcernProfiles
+var ErrInvalidConcernProfile = errors.New("invalid concern profile")
+
+// ErrInvalidConcernThreshold is returned for a threshold that cannot be parsed
+var ErrInvalidConcernThreshold = errors.New("invalid concern threshold")
+
+// Metrics evaluated against levels of concern
+const (
+	CommitsConcernMetric     = "commits"
+	ObjectSizeConcernMetric  = "object-size"
+	DiskSizeConcernMetric    = "disk-size"
+	LargestBlobConcernMetric = "largest-blob" // Object size of the largest blob
+	BlobsConcernMetric       = "blobs"
+	TreesConcernMetric       = "trees"
+)
+
+// ConcernMetrics lists all metrics evaluated against levels of concern in the order they are displayed
+var ConcernMetrics = []string{CommitsConcernMetric, ObjectSizeConcernMetric, DiskSizeConcernMetric, LargestBlobConcernMetric, BlobsConcernMetric, TreesConcernMetric}
+
+// IsSizeConcernMetric reports whether the metric is measured in bytes rather than counted
+func IsSizeConcernMetric(metric string) bool {
+	return metric == ObjectSizeConcernMetric || metric == DiskSizeConcernMetric || metric == LargestBlobConcernMetric
+}
+
+// ConcernThreshold holds the values from which on a metric is at a level of concern
+type ConcernThreshold struct {
+	OnRoad     int64 `json:"onRoad"`     // On-road to concerning (◑) from this value on
+	Concerning int64 `json:"concerning"` // Concerning (●) from this value on
+}
+
+// ConcernThresholds holds the threshold of each metric in ConcernMetrics
+type ConcernThresholds map[string]ConcernThreshold
+
+// Level returns the symbol of the level of concern of the metric's value: ○ for unconcerning, ◑ for
+// on-road to concerning and ● for concerning. Metrics without a threshold are unconcerning.
+func (thresholds ConcernThresholds) Level(metric string, value int64) string {
+	threshold, exists := thresholds[metric]
+	switch {
+	case !exists || value < threshold.OnRoad:
+		return "○"
+	case value < threshold.Concerning:
+		return "◑"
+	default:
+		return "●"
+	}
+}
+
+// DefaultConcernProfile is the name of the profile used unless another one is selected
+const DefaultConcernProfile = "default"
+
+// ConcernProfiles holds named sets of thresholds. The hosted profile follows the limits common
+// hosting providers recommend or enforce for repository and file sizes.
+var ConcernProfiles = map[string]ConcernThresholds{
+	DefaultConcernProfile: {
+		CommitsConcernMetric:     {OnRoad: 1500000, Concerning: 45000000},
+		ObjectSizeConcernMetric:  {OnRoad: 10 * gigabyte, Concerning: 300 * gigabyte},
+		DiskSizeConcernMetric:    {OnRoad: 1 * gigabyte, Concerning: 20 * gigabyte},
+		LargestBlobConcernMetric: {OnRoad: 100 * megabyte, Concerning: 1 * gigabyte},
+		BlobsConcernMetric:       {OnRoad: 5000000, Concerning: 100000000},
+		TreesConcernMetric:       {OnRoad: 5000000, Concerning: 100000000},
+	},
+	"hosted": {
+		CommitsConcernMetric:     {OnRoad: 1500000, Concerning: 45000000},
+		ObjectSizeConcernMetric:  {OnRoad: 10 * gigabyte, Concerning: 300 * gigabyte},
+		DiskSizeConcernMetric:    {OnRoad: 1 * gigabyte, Concerning: 5 * gigabyte},
+		LargestBlobConcernMetric: {OnRoad: 50 * megabyte, Concerning: 100 * megabyte},
+		BlobsConcernMetric:       {OnRoad: 5000000, Concerning: 100000000},
+		TreesConcernMetric:       {OnRoad: 5000000, Concerning: 100000000},
+	},
+}
+
+const (
+	megabyte = 1000 * 1000
+	gigabyte = 1000 * megabyte
+)
+
+// ConcernProfileNames returns the sorted names of all profiles
+func ConcernProfileNames() []string {
+	return slices.Sorted(maps.Keys(ConcernProfiles))
+}
+
+// ParseConcernProfile returns a copy of the thresholds of the profile with the given name
+func ParseConcernProfile(name string) (ConcernThresholds, error) {
+	thresholds, exists := ConcernProfiles[name]
+	if !exists {
+		return nil, fmt.Errorf("%w %q", ErrInvalidConcernProfile, name)
+	}
+	return maps.Clone(thresholds), nil
+}
+
+// ParseConcernThreshold parses a threshold like disk-size=2GB:40GB into the metric and its on-road to
+// concerning and concerning values. Sizes may have a unit, counts are plain numbers.
+func ParseConcernThreshold(value string) (string, ConcernThreshold, error) {
+	metric, levels, found := strings.Cut(value, "=")
+	onRoadValue, concerningValue, separated := strings.Cut(levels, ":")
+	if !found || !separated || !slices.Contains(ConcernMetrics, metric) {
+		return "", ConcernThreshold{}, fmt.Errorf("%w %q", ErrInvalidConcernThreshold, value)
+	}
+
+	parse := func(level string) (int64, error) {
+		if IsSizeConcernMetric(metric) {
+			return utils.ParseSize(level)
+		}
+		return strconv.ParseInt(level, 10, 64)
+	}
+	onRoad, err := parse(onRoadValue)
+	if err != nil {
+		return "", ConcernThreshold{}, fmt.Errorf("%w %q", ErrInvalidConcernThreshold, value)
+	}
+	concerning, err := parse(concerningValue)
+	if err != nil || onRoad <= 0 || concerning < onRoad {
+		return "", ConcernThreshold{}, fmt.Errorf("%w %q", ErrInvalidConcernThreshold, value)
+	}
+	return metric, ConcernThreshold{OnRoad: onRoad, Concerning: concerning}, nil
+}
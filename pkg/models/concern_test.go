@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConcernThresholdsLevel(t *testing.T) {
+	thresholds := ConcernThresholds{DiskSizeConcernMetric: {OnRoad: 1000, Concerning: 20000}}
+	tests := []struct {
+		metric   string
+		value    int64
+		expected string
+	}{
+		{DiskSizeConcernMetric, 999, "○"},
+		{DiskSizeConcernMetric, 1000, "◑"},
+		{DiskSizeConcernMetric, 20000, "●"},
+		{CommitsConcernMetric, 1 << 40, "○"}, // Metrics without a threshold are unconcerning
+	}
+	for _, test := range tests {
+		if level := thresholds.Level(test.metric, test.value); level != test.expected {
+			t.Errorf("Level(%s, %d) = %s, expected %s", test.metric, test.value, level, test.expected)
+		}
+	}
+}
+
+func TestParseConcernProfile(t *testing.T) {
+	thresholds, err := ParseConcernProfile("hosted")
+	if err != nil || thresholds[LargestBlobConcernMetric].Concerning != 100*megabyte {
+		t.Fatalf("ParseConcernProfile(hosted) = %v, %v", thresholds, err)
+	}
+	// Changing the returned thresholds leaves the profile unchanged
+	thresholds[LargestBlobConcernMetric] = ConcernThreshold{}
+	if ConcernProfiles["hosted"][LargestBlobConcernMetric].Concerning != 100*megabyte {
+		t.Error("expected the profile to be copied")
+	}
+	if _, err := ParseConcernProfile("strict"); !errors.Is(err, ErrInvalidConcernProfile) {
+		t.Errorf("expected ErrInvalidConcernProfile, got %v", err)
+	}
+}
+
+func TestParseConcernThreshold(t *testing.T) {
+	metric, threshold, err := ParseConcernThreshold("disk-size=2GB:40GB")
+	if err != nil || metric != DiskSizeConcernMetric || threshold != (ConcernThreshold{OnRoad: 2 * gigabyte, Concerning: 40 * gigabyte}) {
+		t.Errorf("ParseConcernThreshold(disk-size=2GB:40GB) = %s, %+v, %v", metric, threshold, err)
+	}
+	metric, threshold, err = ParseConcernThreshold("commits=3000000:90000000")
+	if err != nil || metric != CommitsConcernMetric || threshold != (ConcernThreshold{OnRoad: 3000000, Concerning: 90000000}) {
+		t.Errorf("ParseConcernThreshold(commits=3000000:90000000) = %s, %+v, %v", metric, threshold, err)
+	}
+	for _, value := range []string{"disk-size=2GB", "size=1GB:2GB", "commits=1M:2M", "disk-size=40GB:2GB", "disk-size=0:1GB"} {
+		if _, _, err := ParseConcernThreshold(value); !errors.Is(err, ErrInvalidConcernThreshold) {
+			t.Errorf("ParseConcernThreshold(%s): expected ErrInvalidConcernThreshold, got %v", value, err)
+		}
+	}
+}
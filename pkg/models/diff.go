@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Metrics of the repository totals compared by a ReportDiff
+const (
+	CommitsMetric    = "commits"
+	AuthorsMetric    = "authors"
+	TreesMetric      = "trees"
+	BlobsMetric      = "blobs"
+	ObjectSizeMetric = "object-size"
+	DiskSizeMetric   = "disk-size"
+)
+
+// ReportDiff holds what changed between two reports of a repository, such as monthly snapshots
+type ReportDiff struct {
+	SchemaVersion int            `json:"schemaVersion"` // ReportSchemaVersion of the compared reports
+	OldAsOf       time.Time      `json:"oldAsOf"`       // Time the old report analyzed the repository as of
+	NewAsOf       time.Time      `json:"newAsOf"`       // Time the new report analyzed the repository as of
+	Totals        []MetricChange `json:"totals"`        // Repository totals both reports contain in the order of the metrics above
+
+	// Files, directories and contributors sections both reports contain. The comparisons of the
+	// other ones are left out, as a report without a section would show all of its data as changed.
+	Sections []Section `json:"sections"`
+
+	// Largest files of the new report that were not among the largest files of the old one
+	NewLargestFiles []FileInformation `json:"newLargestFiles"`
+
+	// Directories that crossed the threshold of the largest directories since the old report, and
+	// directories the old report listed but that fell below the threshold of the new one
+	OldDirectoryThreshold float64          `json:"oldDirectoryThreshold"`
+	NewDirectoryThreshold float64          `json:"newDirectoryThreshold"`
+	DirectoriesAbove      []DirectoryEntry `json:"directoriesAbove"`
+	DirectoriesBelow      []DirectoryEntry `json:"directoriesBelow"`
+
+	// Ranks of the authors and committers with the most commits of all time in the new report,
+	// followed by those that are no longer ranked
+	Authors    []RankingChange `json:"authors"`
+	Committers []RankingChange `json:"committers"`
+}
+
+// MetricChange holds the value of a metric in the old and the new report
+type MetricChange struct {
+	Metric string `json:"metric"`
+	Old    int64  `json:"old"`
+	New    int64  `json:"new"`
+}
+
+// RankingChange holds the rank and commits of a contributor in the old and the new report. A rank
+// of zero means the contributor was not among the contributors with the most commits.
+type RankingChange struct {
+	Name       string `json:"name"`
+	OldRank    int    `json:"oldRank"`
+	NewRank    int    `json:"newRank"`
+	OldCommits int    `json:"oldCommits"`
+	NewCommits int    `json:"newCommits"`
+}
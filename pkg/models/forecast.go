@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidForecastModel is returned for a forecast model other than those in ForecastModels
+var ErrInvalidForecastModel = errors.New("invalid forecast model")
+
+// ForecastModel is the method that projects the growth of the periods following the current one
+type ForecastModel string
+
+// Supported forecast models. The zero value projects linearly.
+const (
+	LinearForecast     ForecastModel = "linear"
+	CompoundForecast   ForecastModel = "compound"
+	RegressionForecast ForecastModel = "regression"
+)
+
+// ForecastModels lists all forecast models, starting with the default
+var ForecastModels = []ForecastModel{LinearForecast, CompoundForecast, RegressionForecast}
+
+// ParseForecastModel returns the forecast model with the given name
+func ParseForecastModel(value string) (ForecastModel, error) {
+	for _, model := range ForecastModels {
+		if string(model) == value {
+			return model, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrInvalidForecastModel, value)
+}
@@ -4,63 +4,82 @@ import "time"
 
 // RepositoryInformation holds information about a git repository
 type RepositoryInformation struct {
-	Remote           string
-	Age              string
-	FirstCommit      string
-	LastCommit       string
-	FirstDate        time.Time
-	TotalCommits     int
-	TotalAuthors     int
-	TotalTrees       int
-	TotalBlobs       int
-	CompressedSize   int64
-	UncompressedSize int64
+	GitDirectory     string    `json:"gitDirectory"`
+	Remote           string    `json:"remote,omitempty"`
+	LastModified     string    `json:"lastModified,omitempty"`
+	MostRecentFetch  string    `json:"mostRecentFetch,omitempty"`
+	Refs             string    `json:"refs"` // Refs whose history is analyzed, set with --refs
+	Age              string    `json:"age"`
+	FirstCommit      string    `json:"firstCommit"`
+	LastCommit       string    `json:"lastCommit"`
+	FirstDate        time.Time `json:"firstDate"`
+	AsOf             time.Time `json:"asOf"`  // Time the repository is analyzed as of, the start of the run unless set with --as-of
+	Since            time.Time `json:"since"` // Start of the analyzed window set with --since, zero for the whole history
+	TotalCommits     int       `json:"totalCommits"`
+	TotalAuthors     int       `json:"totalAuthors"`
+	TotalTrees       int       `json:"totalTrees"`
+	TotalBlobs       int       `json:"totalBlobs"`
+	CompressedSize   int64     `json:"compressedSize"`
+	UncompressedSize int64     `json:"uncompressedSize"`
 }
 
 // GrowthStatistics holds statistics about repository growth
 type GrowthStatistics struct {
-	Year         int
-	Authors      int
-	Commits      int
-	Trees        int
-	Blobs        int
-	Compressed   int64
-	Uncompressed int64
-	RunTime      time.Duration
-	LargestFiles []FileInformation
+	Period       Period            `json:"period"`
+	Authors      int               `json:"authors"`
+	Commits      int               `json:"commits"`
+	Trees        int               `json:"trees"`
+	Blobs        int               `json:"blobs"`
+	Compressed   int64             `json:"compressed"`
+	Uncompressed int64             `json:"uncompressed"`
+	LargestFiles []FileInformation `json:"-"`
 
-	// Delta values (year-over-year changes)
-	AuthorsDelta      int
-	CommitsDelta      int
-	TreesDelta        int
-	BlobsDelta        int
-	CompressedDelta   int64
-	UncompressedDelta int64
+	// Delta values (changes from the previous period)
+	AuthorsDelta      int   `json:"authorsDelta"`
+	CommitsDelta      int   `json:"commitsDelta"`
+	TreesDelta        int   `json:"treesDelta"`
+	BlobsDelta        int   `json:"blobsDelta"`
+	CompressedDelta   int64 `json:"compressedDelta"`
+	UncompressedDelta int64 `json:"uncompressedDelta"`
 
 	// Percentage of total
-	AuthorsPercent      float64
-	CommitsPercent      float64
-	TreesPercent        float64
-	BlobsPercent        float64
-	CompressedPercent   float64
-	UncompressedPercent float64
+	AuthorsPercent      float64 `json:"authorsPercent"`
+	CommitsPercent      float64 `json:"commitsPercent"`
+	TreesPercent        float64 `json:"treesPercent"`
+	BlobsPercent        float64 `json:"blobsPercent"`
+	CompressedPercent   float64 `json:"compressedPercent"`
+	UncompressedPercent float64 `json:"uncompressedPercent"`
 
 	// Delta percentage changes (Δ%)
-	AuthorsDeltaPercent      float64
-	CommitsDeltaPercent      float64
-	TreesDeltaPercent        float64
-	BlobsDeltaPercent        float64
-	CompressedDeltaPercent   float64
-	UncompressedDeltaPercent float64
+	AuthorsDeltaPercent      float64 `json:"authorsDeltaPercent"`
+	CommitsDeltaPercent      float64 `json:"commitsDeltaPercent"`
+	TreesDeltaPercent        float64 `json:"treesDeltaPercent"`
+	BlobsDeltaPercent        float64 `json:"blobsDeltaPercent"`
+	CompressedDeltaPercent   float64 `json:"compressedDeltaPercent"`
+	UncompressedDeltaPercent float64 `json:"uncompressedDeltaPercent"`
+
+	// Range the totals of an estimate are expected in, nil for historic statistics and for estimates
+	// without enough history to tell the variance of the deltas
+	Low  *GrowthBound `json:"low,omitempty"`
+	High *GrowthBound `json:"high,omitempty"`
+}
+
+// GrowthBound holds the low or high end of the range of an estimate's totals
+type GrowthBound struct {
+	Authors      int   `json:"authors"`
+	Commits      int   `json:"commits"`
+	Compressed   int64 `json:"compressed"`
+	Uncompressed int64 `json:"uncompressed"`
 }
 
 // FileInformation holds information about a file in the repository
 type FileInformation struct {
-	Path             string
-	Blobs            int
-	CompressedSize   int64
-	UncompressedSize int64
-	LastChange       time.Time
+	Path             string    `json:"path"`
+	Blobs            int       `json:"blobs"`
+	CompressedSize   int64     `json:"compressedSize"`
+	UncompressedSize int64     `json:"uncompressedSize"`
+	LargestBlob      int64     `json:"largestBlob"` // Object size of the largest blob
+	LastChange       time.Time `json:"-"`
 }
 
 // GitObject represents a git object with its details
@@ -71,28 +90,28 @@ type GitObject struct {
 	Additional       string // typically the file path if available
 }
 
-// RateStatistics holds commit rate statistics for a specific year
+// RateStatistics holds commit rate statistics for a specific period
 type RateStatistics struct {
-	Year                 int
-	TotalCommits         int
-	ActiveAuthors        int     // Number of unique authors with commits this year
-	AverageCommitsPerDay float64
-	DailyPeakP95         int // 95th percentile of daily commits
-	DailyPeakP99         int // 99th percentile of daily commits
-	DailyPeakP100        int // Maximum daily commits
-	HourlyPeakP95        int // 95th percentile of hourly commits
-	HourlyPeakP99        int // 99th percentile of hourly commits
-	HourlyPeakP100       int // Maximum hourly commits
-	MinutelyPeakP95      int // 95th percentile of commits per minute
-	MinutelyPeakP99      int // 99th percentile of commits per minute
-	MinutelyPeakP100     int // Maximum commits per minute
-	PercentageOfTotal    float64
-	MergeCommits         int     // Commits with >1 parent
-	DirectCommits        int     // Regular commits
-	MergeRatio           float64 // Percentage of commits that are merges
-	BusiestDay           string  // Date with most commits
-	BusiestDayCommits    int     // Number of commits on busiest day
-	WorkdayCommits       int     // Commits during weekdays
-	WeekendCommits       int     // Commits during weekends
-	WorkdayWeekendRatio  float64 // Ratio of workday to weekend commits
+	Period               Period  `json:"period"`
+	TotalCommits         int     `json:"totalCommits"`
+	ActiveAuthors        int     `json:"activeAuthors"` // Number of unique authors with commits this period
+	AverageCommitsPerDay float64 `json:"averageCommitsPerDay"`
+	DailyPeakP95         int     `json:"dailyPeakP95"`     // 95th percentile of daily commits
+	DailyPeakP99         int     `json:"dailyPeakP99"`     // 99th percentile of daily commits
+	DailyPeakP100        int     `json:"dailyPeakP100"`    // Maximum daily commits
+	HourlyPeakP95        int     `json:"hourlyPeakP95"`    // 95th percentile of hourly commits
+	HourlyPeakP99        int     `json:"hourlyPeakP99"`    // 99th percentile of hourly commits
+	HourlyPeakP100       int     `json:"hourlyPeakP100"`   // Maximum hourly commits
+	MinutelyPeakP95      int     `json:"minutelyPeakP95"`  // 95th percentile of commits per minute
+	MinutelyPeakP99      int     `json:"minutelyPeakP99"`  // 99th percentile of commits per minute
+	MinutelyPeakP100     int     `json:"minutelyPeakP100"` // Maximum commits per minute
+	PercentageOfTotal    float64 `json:"percentageOfTotal"`
+	MergeCommits         int     `json:"mergeCommits"`        // Commits with >1 parent
+	DirectCommits        int     `json:"directCommits"`       // Regular commits
+	MergeRatio           float64 `json:"mergeRatio"`          // Percentage of commits that are merges
+	BusiestDay           string  `json:"busiestDay"`          // Date with most commits
+	BusiestDayCommits    int     `json:"busiestDayCommits"`   // Number of commits on busiest day
+	WorkdayCommits       int     `json:"workdayCommits"`      // Commits during weekdays
+	WeekendCommits       int     `json:"weekendCommits"`      // Commits during weekends
+	WorkdayWeekendRatio  float64 `json:"workdayWeekendRatio"` // Ratio of workday to weekend commits
 }
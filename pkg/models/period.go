@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidInterval is returned for an interval other than month, quarter or year
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// Interval is the length of the periods statistics are grouped by
+type Interval string
+
+// Supported intervals. The zero value groups by year.
+const (
+	MonthInterval   Interval = "month"
+	QuarterInterval Interval = "quarter"
+	YearInterval    Interval = "year"
+)
+
+// ParseInterval returns the interval with the given name
+func ParseInterval(value string) (Interval, error) {
+	switch interval := Interval(value); interval {
+	case MonthInterval, QuarterInterval, YearInterval:
+		return interval, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrInvalidInterval, value)
+}
+
+// PeriodsPerYear returns the number of periods of the interval within a year
+func (interval Interval) PeriodsPerYear() int {
+	switch interval {
+	case MonthInterval:
+		return 12
+	case QuarterInterval:
+		return 4
+	}
+	return 1
+}
+
+// Noun returns the name of a single period of the interval, for example "quarter"
+func (interval Interval) Noun() string {
+	if interval == "" {
+		return string(YearInterval)
+	}
+	return string(interval)
+}
+
+// Title returns the name of the interval as a table column title, for example "Quarter"
+func (interval Interval) Title() string {
+	noun := interval.Noun()
+	return strings.ToUpper(noun[:1]) + noun[1:]
+}
+
+// PeriodOf returns the period of the interval containing the time in its location
+func (interval Interval) PeriodOf(moment time.Time) Period {
+	perYear := interval.PeriodsPerYear()
+	return Period{Interval: interval, Index: moment.Year()*perYear + (int(moment.Month())-1)*perYear/12}
+}
+
+// Period is a calendar month, quarter or year. The periods of an interval are numbered
+// consecutively, so period.Add(1) is the following period and indexes can be compared.
+type Period struct {
+	Interval Interval
+	Index    int // Number of periods since the start of year 0
+}
+
+// Add returns the period count periods after the period, or before it if count is negative
+func (period Period) Add(count int) Period {
+	return Period{Interval: period.Interval, Index: period.Index + count}
+}
+
+// Year returns the year the period belongs to
+func (period Period) Year() int {
+	return period.Index / period.Interval.PeriodsPerYear()
+}
+
+// number returns the one-based number of the period within its year
+func (period Period) number() int {
+	return period.Index%period.Interval.PeriodsPerYear() + 1
+}
+
+// Start returns the beginning of the period in UTC
+func (period Period) Start() time.Time {
+	month := time.Month((period.number()-1)*12/period.Interval.PeriodsPerYear() + 1)
+	return time.Date(period.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the beginning of the following period in UTC
+func (period Period) End() time.Time {
+	return period.Add(1).Start()
+}
+
+// In returns the period of the interval that contains the period, for example the quarter of a month
+func (period Period) In(interval Interval) Period {
+	return interval.PeriodOf(period.Start())
+}
+
+// String returns the period as 2024 for years, 2024-Q3 for quarters and 2024-07 for months
+func (period Period) String() string {
+	switch period.Interval {
+	case MonthInterval:
+		return fmt.Sprintf("%d-%02d", period.Year(), period.number())
+	case QuarterInterval:
+		return fmt.Sprintf("%d-Q%d", period.Year(), period.number())
+	}
+	return strconv.Itoa(period.Year())
+}
+
+// ParsePeriod parses a period in the format returned by Period.String
+func ParsePeriod(text string) (Period, error) {
+	var year, number int
+	if _, err := fmt.Sscanf(text, "%4d-Q%d", &year, &number); err == nil && len(text) == 7 && number >= 1 && number <= 4 {
+		return Period{Interval: QuarterInterval, Index: year*4 + number - 1}, nil
+	}
+	if _, err := fmt.Sscanf(text, "%4d-%2d", &year, &number); err == nil && len(text) == 7 && number >= 1 && number <= 12 {
+		return Period{Interval: MonthInterval, Index: year*12 + number - 1}, nil
+	}
+	if year, err := strconv.Atoi(text); err == nil {
+		return Period{Interval: YearInterval, Index: year}, nil
+	}
+	return Period{}, fmt.Errorf("invalid period %q, expected a year like 2024, a quarter like 2024-Q3 or a month like 2024-07", text)
+}
+
+// MarshalText encodes the period as returned by String, so it is readable in JSON reports
+func (period Period) MarshalText() ([]byte, error) {
+	return []byte(period.String()), nil
+}
+
+// UnmarshalText decodes a period encoded by MarshalText
+func (period *Period) UnmarshalText(text []byte) error {
+	parsed, err := ParsePeriod(string(text))
+	if err != nil {
+		return err
+	}
+	*period = parsed
+	return nil
+}
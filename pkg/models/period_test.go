@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPeriod(t *testing.T) {
+	moment := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		interval Interval
+		want     string
+		start    time.Time
+		end      time.Time
+	}{
+		{MonthInterval, "2024-08", time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{QuarterInterval, "2024-Q3", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{YearInterval, "2024", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(string(test.interval), func(t *testing.T) {
+			period := test.interval.PeriodOf(moment)
+			if period.String() != test.want {
+				t.Errorf("PeriodOf(%v) = %s, want %s", moment, period, test.want)
+			}
+			if !period.Start().Equal(test.start) || !period.End().Equal(test.end) {
+				t.Errorf("expected %s to last from %v to %v, got %v to %v", period, test.start, test.end, period.Start(), period.End())
+			}
+			if parsed, err := ParsePeriod(test.want); err != nil || parsed != period {
+				t.Errorf("ParsePeriod(%q) = %v, %v, want %v", test.want, parsed, err, period)
+			}
+		})
+	}
+
+	december := MonthInterval.PeriodOf(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC))
+	if next := december.Add(1); next.String() != "2025-01" {
+		t.Errorf("expected 2025-01 after %s, got %s", december, next)
+	}
+	if quarter := december.In(QuarterInterval); quarter.String() != "2024-Q4" {
+		t.Errorf("expected %s to be in 2024-Q4, got %s", december, quarter)
+	}
+	if _, err := ParsePeriod("2024-13"); err == nil {
+		t.Error("expected an error for month 13")
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	if interval, err := ParseInterval("quarter"); err != nil || interval != QuarterInterval {
+		t.Errorf("ParseInterval(quarter) = %q, %v", interval, err)
+	}
+	if _, err := ParseInterval("week"); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("expected ErrInvalidInterval for week, got %v", err)
+	}
+}
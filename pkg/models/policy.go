@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConcernLevel is returned for a level of concern other than those in ConcernLevels
+var ErrInvalidConcernLevel = errors.New("invalid concern level")
+
+// ErrInvalidPercentage is returned for a percentage that is not a positive number
+var ErrInvalidPercentage = errors.New("invalid percentage")
+
+// UnconcerningConcernLevel is the level of metrics below the on-road to concerning threshold
+const UnconcerningConcernLevel = "unconcerning"
+
+// ConcernLevels lists the levels of concern from the lowest to the highest
+var ConcernLevels = []string{UnconcerningConcernLevel, OnRoadConcernLevel, ConcerningConcernLevel}
+
+// ParseConcernLevel returns the level of concern with the given name
+func ParseConcernLevel(value string) (string, error) {
+	if !slices.Contains(ConcernLevels, value) {
+		return "", fmt.Errorf("%w %q", ErrInvalidConcernLevel, value)
+	}
+	return value, nil
+}
+
+// ParsePercentage parses a positive percentage such as 25 or 12.5%
+func ParsePercentage(value string) (float64, error) {
+	percentage, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%")), 64)
+	if err != nil || percentage <= 0 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidPercentage, value)
+	}
+	return percentage, nil
+}
+
+// Policy holds the limits a repository is checked against. Zero values do not limit anything.
+type Policy struct {
+	MaxDiskSize         int64    `json:"maxDiskSize,omitempty"`         // On-disk size of all objects
+	MaxBlobSize         int64    `json:"maxBlobSize,omitempty"`         // Object size of any blob
+	MaxConcernLevel     string   `json:"maxConcernLevel,omitempty"`     // Highest level of concern any metric may reach
+	MaxGrowthPercent    float64  `json:"maxGrowthPercent,omitempty"`    // Growth of the on-disk size within the last complete period
+	ForbiddenExtensions []string `json:"forbiddenExtensions,omitempty"` // File extensions no file may have, such as .zip
+}
+
+// Names of the limits of a policy
+const (
+	MaxDiskSizeRule        = "max-disk-size"
+	MaxBlobSizeRule        = "max-blob-size"
+	MaxConcernLevelRule    = "max-concern-level"
+	MaxGrowthRule          = "max-growth"
+	ForbiddenExtensionRule = "forbid-extension"
+)
+
+// Rules returns the names of the limits the policy sets
+func (policy Policy) Rules() []string {
+	var rules []string
+	if policy.MaxDiskSize > 0 {
+		rules = append(rules, MaxDiskSizeRule)
+	}
+	if policy.MaxBlobSize > 0 {
+		rules = append(rules, MaxBlobSizeRule)
+	}
+	if policy.MaxConcernLevel != "" {
+		rules = append(rules, MaxConcernLevelRule)
+	}
+	if policy.MaxGrowthPercent > 0 {
+		rules = append(rules, MaxGrowthRule)
+	}
+	if len(policy.ForbiddenExtensions) > 0 {
+		rules = append(rules, ForbiddenExtensionRule)
+	}
+	return rules
+}
+
+// PolicyViolation describes a limit of a policy the repository exceeds
+type PolicyViolation struct {
+	Rule    string `json:"rule"` // Name of the violated limit, such as max-disk-size
+	Message string `json:"message"`
+}
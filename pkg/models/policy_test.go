@@ -0,0 +1,28 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseConcernLevel(t *testing.T) {
+	if level, err := ParseConcernLevel("on-road"); err != nil || level != OnRoadConcernLevel {
+		t.Errorf("ParseConcernLevel(on-road) = %s, %v", level, err)
+	}
+	if _, err := ParseConcernLevel("high"); !errors.Is(err, ErrInvalidConcernLevel) {
+		t.Errorf("expected ErrInvalidConcernLevel, got %v", err)
+	}
+}
+
+func TestParsePercentage(t *testing.T) {
+	for value, expected := range map[string]float64{"25": 25, "12.5%": 12.5, " 100 % ": 100} {
+		if percentage, err := ParsePercentage(value); err != nil || percentage != expected {
+			t.Errorf("ParsePercentage(%q) = %v, %v, expected %v", value, percentage, err, expected)
+		}
+	}
+	for _, value := range []string{"", "0", "-5%", "a lot"} {
+		if _, err := ParsePercentage(value); !errors.Is(err, ErrInvalidPercentage) {
+			t.Errorf("ParsePercentage(%q): expected ErrInvalidPercentage, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReportSchemaVersion is the version of the machine-readable report format.
+// It is incremented whenever fields are renamed, removed or change meaning.
+const ReportSchemaVersion = 2
+
+// Section identifies a part of the report
+type Section string
+
+// Report sections in the order they are collected and displayed
+const (
+	RunSection                 Section = "run"
+	RepositorySection          Section = "repository"
+	GrowthSection              Section = "growth"
+	ConcernSection             Section = "concern"
+	BacktestSection            Section = "backtest" // Only collected on request
+	FileExtensionsSection      Section = "extensions"
+	FileExtensionGrowthSection Section = "extension-growth"
+	DirectoriesSection         Section = "directories"
+	FilesSection               Section = "files"
+	RateOfChangesSection       Section = "rates"
+	ContributorsSection        Section = "contributors"
+	PolicySection              Section = "policy" // Only collected when checking a policy
+	RangeSection               Section = "range"  // Only collected, instead of the history, when measuring a range
+)
+
+// ErrInvalidSection is returned for a name that is not one of the SelectableSections
+var ErrInvalidSection = errors.New("invalid section")
+
+// SelectableSections are the sections of the history analysis that can be selected, in the order
+// they are collected by default. The run and repository information are always collected.
+var SelectableSections = []Section{
+	GrowthSection, ConcernSection, BacktestSection, FileExtensionsSection, FileExtensionGrowthSection,
+	DirectoriesSection, FilesSection, RateOfChangesSection, ContributorsSection,
+}
+
+// ParseSection returns the selectable section with the given name
+func ParseSection(value string) (Section, error) {
+	if section := Section(value); slices.Contains(SelectableSections, section) {
+		return section, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrInvalidSection, value)
+}
+
+// Report holds all metrics collected for a repository in a single document
+type Report struct {
+	SchemaVersion         int                         `json:"schemaVersion"`
+	Incomplete            bool                        `json:"incomplete,omitempty"` // The analysis was interrupted and sections are missing
+	Interval              Interval                    `json:"interval"`             // Length of the periods growth, rates and contributors are grouped by
+	ForecastModel         ForecastModel               `json:"forecastModel"`        // Method the estimates of the following periods are projected with
+	Run                   RunInformation              `json:"run"`
+	Repository            *RepositoryInformation      `json:"repository,omitempty"` // Not set for the objects of a range
+	Growth                []GrowthStatistics          `json:"growth"`
+	Estimates             []GrowthStatistics          `json:"estimates"`
+	ConcernThresholds     ConcernThresholds           `json:"concernThresholds"` // Thresholds the levels of concern are evaluated with
+	ConcernProjections    []ConcernProjection         `json:"concernProjections"`
+	Backtest              *BacktestReport             `json:"backtest,omitempty"`
+	LargestFileExtensions FileExtensionReport         `json:"largestFileExtensions"`
+	FileExtensionGrowth   []FileExtensionGrowthReport `json:"fileExtensionGrowth"`
+	LargestDirectories    DirectoryReport             `json:"largestDirectories"`
+	LargestFiles          LargestFilesReport          `json:"largestFiles"`
+	RateOfChanges         RateOfChangesReport         `json:"rateOfChanges"`
+	Authors               ContributorReport           `json:"authors"`
+	Committers            ContributorReport           `json:"committers"`
+	Range                 *RangeReport                `json:"range,omitempty"`      // Objects of the measured range, only set instead of the history sections
+	Policy                *Policy                     `json:"policy,omitempty"`     // Limits the repository was checked against
+	Violations            []PolicyViolation           `json:"violations,omitempty"` // Limits of the policy the repository exceeds
+}
+
+// Collected reports whether the report contains the data of the section. The run information lists
+// a phase for each section that was collected completely, sections that were not selected or were
+// interrupted are left empty.
+func (report Report) Collected(section Section) bool {
+	return slices.ContainsFunc(report.Run.Phases, func(phase PhaseInformation) bool { return phase.Section == section })
+}
+
+// RangeReport holds the objects a range of commits, such as a push, adds to the repository
+type RangeReport struct {
+	Names        []string          `json:"names"` // Ranges or updated refs the objects were measured for
+	Commits      int               `json:"commits"`
+	Trees        int               `json:"trees"`
+	Blobs        int               `json:"blobs"`
+	Compressed   int64             `json:"compressed"`
+	Uncompressed int64             `json:"uncompressed"`
+	LargestBlobs []FileInformation `json:"largestBlobs"` // Files with the largest new blobs, largest first
+}
+
+// RunInformation holds information about the machine and versions used for a run
+type RunInformation struct {
+	StartTime         time.Time `json:"startTime"`
+	CPUCores          int       `json:"cpuCores"`
+	MemoryGigabytes   int       `json:"memoryGigabytes"`
+	OperatingSystem   string    `json:"operatingSystem"`
+	Chip              string    `json:"chip"`
+	Terminal          string    `json:"terminal"`
+	GitMetricsVersion string    `json:"gitMetricsVersion"`
+	GitVersion        string    `json:"gitVersion"`
+
+	// Set when the run has finished
+	Duration        time.Duration      `json:"duration"`
+	MemoryFootprint uint64             `json:"memoryFootprint"`
+	Phases          []PhaseInformation `json:"phases"`
+}
+
+// PhaseInformation holds how long collecting a section took and the peak heap size meanwhile
+type PhaseInformation struct {
+	Section    Section       `json:"section"`
+	Duration   time.Duration `json:"duration"`
+	PeakMemory uint64        `json:"peakMemory"`
+}
+
+// ConcernProjection holds when a metric is projected to reach a level of concern
+type ConcernProjection struct {
+	Metric    string `json:"metric"`    // One of ConcernMetrics
+	Level     string `json:"level"`     // on-road or concerning
+	Threshold int64  `json:"threshold"` // Value from which on the metric is at the level
+	Value     int64  `json:"value"`     // Current value of the metric
+	Reached   bool   `json:"reached"`   // The current totals are at or above the level
+	// Period the metric is projected to reach the level in, nil if reached or not within the
+	// projected periods
+	Period *Period `json:"period,omitempty"`
+	// Projected beyond the estimates by continuing the delta of the last estimate
+	Extrapolated bool `json:"extrapolated"`
+}
+
+// Levels of concern of a ConcernProjection
+const (
+	OnRoadConcernLevel     = "on-road"
+	ConcerningConcernLevel = "concerning"
+)
+
+// BacktestReport holds how well the forecast models would have predicted the repository's growth
+// if they had been run at the end of each past period
+type BacktestReport struct {
+	First   Period           `json:"first"` // First period at the end of which forecasts were made
+	Last    Period           `json:"last"`  // Last period at the end of which forecasts were made
+	Results []BacktestResult `json:"results"`
+}
+
+// BacktestResult holds the errors of the forecasts of a model for a number of periods ahead. Errors
+// are percentages of the actual totals, biases are negative if the forecasts were too low on average.
+type BacktestResult struct {
+	Model             ForecastModel `json:"model"`
+	Horizon           int           `json:"horizon"`   // Number of periods between the forecast and the forecast period
+	Forecasts         int           `json:"forecasts"` // Number of forecasts compared with actual totals
+	CommitsError      float64       `json:"commitsError"`
+	CommitsBias       float64       `json:"commitsBias"`
+	UncompressedError float64       `json:"uncompressedError"`
+	UncompressedBias  float64       `json:"uncompressedBias"`
+	CompressedError   float64       `json:"compressedError"`
+	CompressedBias    float64       `json:"compressedBias"`
+}
+
+// FileExtensionStatistics holds aggregated statistics for one file extension
+type FileExtensionStatistics struct {
+	Extension        string `json:"extension"`
+	Files            int    `json:"files"`
+	Blobs            int    `json:"blobs"`
+	CompressedSize   int64  `json:"compressedSize"`
+	UncompressedSize int64  `json:"uncompressedSize"`
+}
+
+// FileExtensionReport holds the largest file extensions and the totals over all extensions
+type FileExtensionReport struct {
+	Extensions     []FileExtensionStatistics `json:"extensions"`
+	Total          FileExtensionStatistics   `json:"total"`
+	ExtensionCount int                       `json:"extensionCount"`
+}
+
+// FileExtensionGrowth holds the on-disk size growth of one extension within a period
+type FileExtensionGrowth struct {
+	Extension string  `json:"extension"`
+	Growth    int64   `json:"growth"`
+	Percent   float64 `json:"percent"` // Share of the period's total extension growth
+}
+
+// FileExtensionGrowthReport holds the extensions with the largest growth in a period
+type FileExtensionGrowthReport struct {
+	Period     Period                `json:"period"`
+	Extensions []FileExtensionGrowth `json:"extensions"`
+}
+
+// DirectoryEntry holds a directory or file that contributes significantly to the on-disk size
+type DirectoryEntry struct {
+	Name                  string `json:"name"`
+	Path                  string `json:"path"`
+	Level                 int    `json:"level"`
+	IsFile                bool   `json:"isFile"`
+	ExistsInDefaultBranch bool   `json:"existsInDefaultBranch"`
+	Blobs                 int    `json:"blobs"`
+	CompressedSize        int64  `json:"compressedSize"`
+	TreePrefix            string `json:"-"` // Tree drawing characters used by the text output
+}
+
+// DirectoryReport holds the largest directories and files in tree order, starting with the root
+type DirectoryReport struct {
+	DefaultBranch       string           `json:"defaultBranch,omitempty"`
+	DefaultBranchError  string           `json:"defaultBranchError,omitempty"`
+	Threshold           float64          `json:"threshold"` // Percentage of the on-disk size from which on entries are listed
+	TotalBlobs          int              `json:"totalBlobs"`
+	TotalCompressedSize int64            `json:"totalCompressedSize"`
+	Entries             []DirectoryEntry `json:"entries"`
+}
+
+// LargestFilesReport holds the largest files and the totals they are compared against
+type LargestFilesReport struct {
+	Files               []FileInformation `json:"files"`
+	TotalBlobs          int               `json:"totalBlobs"`
+	TotalCompressedSize int64             `json:"totalCompressedSize"`
+	TotalFiles          int               `json:"totalFiles"`
+}
+
+// RateOfChangesReport holds commit rate statistics per period for a branch or, if the analyzed refs
+// are limited, for all commits reachable from them
+type RateOfChangesReport struct {
+	Branch  string           `json:"branch,omitempty"`
+	Refs    string           `json:"refs,omitempty"`
+	Periods []RateStatistics `json:"periods"`
+}
+
+// Contributor holds a contributor name and their number of commits
+type Contributor struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// ContributorPeriod holds the contributors with most commits within a period
+type ContributorPeriod struct {
+	Period            Period        `json:"period"`
+	TotalCommits      int           `json:"totalCommits"`
+	TotalContributors int           `json:"totalContributors"`
+	Top               []Contributor `json:"top"`
+}
+
+// ContributorReport holds the contributors with most commits per period and of all time
+type ContributorReport struct {
+	Periods           []ContributorPeriod `json:"periods"`
+	AllTime           []Contributor       `json:"allTime"`
+	AllTimeCommits    int                 `json:"allTimeCommits"`
+	TotalContributors int                 `json:"totalContributors"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSection(t *testing.T) {
+	if section, err := ParseSection("extension-growth"); err != nil || section != FileExtensionGrowthSection {
+		t.Errorf("ParseSection(extension-growth) = %q, %v", section, err)
+	}
+	// The run and repository information and the policy check cannot be selected
+	for _, value := range []string{"run", "repository", "policy", "Files", ""} {
+		if _, err := ParseSection(value); !errors.Is(err, ErrInvalidSection) {
+			t.Errorf("ParseSection(%q): expected ErrInvalidSection, got %v", value, err)
+		}
+	}
+}
@@ -36,7 +36,7 @@ func (s *Spinner) Next() string {
 
 // ProgressState tracks the current progress state
 type ProgressState struct {
-	Year               int
+	Period             models.Period
 	Statistics         models.GrowthStatistics
 	PreviousStatistics models.GrowthStatistics
 	Active             bool
@@ -56,6 +56,9 @@ var (
 	// ShowProgress determines whether to display progress
 	ShowProgress bool
 
+	// ConcernThresholds determine the levels of concern shown in the progress line
+	ConcernThresholds = models.ConcernProfiles[models.DefaultConcernProfile]
+
 	// spinnerQuitChannel is used to signal the spinner goroutine to stop
 	spinnerQuitChannel chan struct{}
 
@@ -164,17 +167,17 @@ func UpdateProgress() {
 	statistics := snapshot.Statistics
 	previous := snapshot.PreviousStatistics
 
-	// Calculate deltas from previous year
+	// Calculate deltas from previous period
 	commitsDelta := statistics.Commits - previous.Commits
 	uncompressedDelta := statistics.Uncompressed - previous.Uncompressed
 	compressedDelta := statistics.Compressed - previous.Compressed
 
-	commitsConcernLevel := utils.GetConcernLevel("commits", int64(statistics.Commits))
-	objectSizeConcernLevel := utils.GetConcernLevel("object-size", statistics.Uncompressed)
-	onDiskSizeConcernLevel := utils.GetConcernLevel("disk-size", statistics.Compressed)
+	commitsConcernLevel := ConcernThresholds.Level(models.CommitsConcernMetric, int64(statistics.Commits))
+	objectSizeConcernLevel := ConcernThresholds.Level(models.ObjectSizeConcernMetric, statistics.Uncompressed)
+	onDiskSizeConcernLevel := ConcernThresholds.Level(models.DiskSizeConcernMetric, statistics.Compressed)
 
-	progressLine := fmt.Sprintf("%-6s %14s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s",
-		fmt.Sprintf("%d %s", snapshot.Year, ProgressSpinner.Next()),
+	progressLine := fmt.Sprintf("%-9s %11s %10s %5s %3s │%14s %12s %5s %3s │%14s %12s %5s %3s",
+		fmt.Sprintf("%s %s", snapshot.Period, ProgressSpinner.Next()),
 		utils.FormatNumber(statistics.Commits),
 		formatDelta(commitsDelta),
 		"...",
@@ -211,14 +214,14 @@ func UpdateProgress() {
 }
 
 // StartProgress starts progress tracking
-func StartProgress(year int, statistics models.GrowthStatistics, previousStatistics models.GrowthStatistics, programStart time.Time) {
+func StartProgress(period models.Period, statistics models.GrowthStatistics, previousStatistics models.GrowthStatistics, programStart time.Time) {
 	// Stop any existing spinner goroutine before starting a new one
 	StopProgress()
 
 	// Always update the state
 	progressStateMutex.Lock()
 	CurrentProgress = ProgressState{
-		Year:               year,
+		Period:             period,
 		Statistics:         statistics,
 		PreviousStatistics: previousStatistics,
 		Active:             true,
@@ -348,3 +351,9 @@ func StopSectionSpinner() {
 	}
 }
 
+// StopAll stops the progress row and the section spinner and clears their output, leaving the
+// cursor at the start of the line where the next output continues
+func StopAll() {
+	StopProgress()
+	StopSectionSpinner()
+}
@@ -30,7 +30,7 @@ func TestShowProgressFlag(t *testing.T) {
 	ShowProgress = false
 
 	// When ShowProgress is false, these functions should return without errors
-	StartProgress(2023, models.GrowthStatistics{}, models.GrowthStatistics{}, time.Now())
+	StartProgress(models.Period{Interval: models.YearInterval, Index: 2023}, models.GrowthStatistics{}, models.GrowthStatistics{}, time.Now())
 	UpdateProgress()
 	StopProgress()
 
@@ -39,7 +39,7 @@ func TestShowProgressFlag(t *testing.T) {
 
 	// These should execute without error but we can't easily verify console output
 	// in unit tests without capturing stdout
-	StartProgress(2023, models.GrowthStatistics{}, models.GrowthStatistics{}, time.Now())
+	StartProgress(models.Period{Interval: models.YearInterval, Index: 2023}, models.GrowthStatistics{}, models.GrowthStatistics{}, time.Now())
 	time.Sleep(10 * time.Millisecond) // Small delay
 	StopProgress()
 }
@@ -56,7 +56,7 @@ func TestProgressState(t *testing.T) {
 	ShowProgress = false // Disable actual output during test
 
 	// Test setting and retrieving values
-	testYear := 2023
+	testPeriod := models.Period{Interval: models.QuarterInterval, Index: 2023*4 + 2}
 	testStats := models.GrowthStatistics{
 		Commits:    100,
 		Trees:      50,
@@ -65,10 +65,10 @@ func TestProgressState(t *testing.T) {
 	}
 	startTime := time.Now()
 
-	StartProgress(testYear, testStats, models.GrowthStatistics{}, startTime)
+	StartProgress(testPeriod, testStats, models.GrowthStatistics{}, startTime)
 
-	if CurrentProgress.Year != testYear {
-		t.Errorf("Expected Year to be %d, got %d", testYear, CurrentProgress.Year)
+	if CurrentProgress.Period != testPeriod {
+		t.Errorf("Expected Period to be %v, got %v", testPeriod, CurrentProgress.Period)
 	}
 
 	if CurrentProgress.Statistics.Commits != testStats.Commits {
@@ -3,7 +3,6 @@ package requirements
 import (
 	"fmt"
 	"os/exec"
-	"runtime"
 )
 
 // CheckRequirements validates if required dependencies are available
@@ -18,18 +17,5 @@ func CheckRequirements() bool {
 		allRequirementsMet = false
 	}
 
-	// Check bash on Windows
-	// Bash is required because git.GetGrowthStats relies on a pipe that only bash can handle
-	// We might remove this requirement in the future
-	if runtime.GOOS == "windows" {
-		if _, err := exec.LookPath("bash"); err != nil {
-			fmt.Println("bash is not installed or not in PATH")
-			fmt.Println("Please install Git Bash or make sure bash is available in your PATH")
-			fmt.Println("You can use Git Bash or wsl")
-			fmt.Println("")
-			allRequirementsMet = false
-		}
-	}
-
 	return allRequirementsMet
 }
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,49 +15,12 @@ import (
 	"golang.org/x/term"
 )
 
-// GetConcernLevel returns the Level of Concern symbol based on the metric type and value
-// ○ for "Unconcerning", ◑ for "On-road to concerning", ● for "Concerning"
-// Uses level-based thresholds: no concern (< level 1), mid-concern (< level threshold), concern (≥ level threshold)
-func GetConcernLevel(metricType string, value int64) string {
-	var levelStep int64
-	var concernThreshold float64
-
-	switch metricType {
-	case "commits":
-		// Level step: 1.5 million commits per level
-		levelStep = 1500000
-		concernThreshold = 30.0
-	case "disk-size":
-		// Level step: 1 GB per level (based on original 1GB/10GB thresholds)
-		levelStep = 1000 * 1000 * 1000 // 1 GB
-		concernThreshold = 20.0
-	case "object-size":
-		// Level step: 10 GB per level (based on original 10GB/160GB thresholds)
-		levelStep = 10 * 1000 * 1000 * 1000 // 10 GB
-		concernThreshold = 30.0
-	default:
-		return "○" // default to unconcerning
-	}
-
-	// Calculate level based on value and step
-	level := float64(value) / float64(levelStep)
-
-	// Apply concern thresholds
-	if level < 1.0 {
-		return "○" // No concern: below level 1
-	} else if level < concernThreshold {
-		return "◑" // Mid-concern: level 1 to below concern threshold
-	} else {
-		return "●" // Concern: at concern threshold and above
-	}
-}
-
-// DebugPrint prints debug information if debug mode is enabled
+// DebugPrint prints debug information to stderr if debug mode is enabled
 func DebugPrint(debug bool, format string, args ...interface{}) {
 	if debug {
-		fmt.Printf("[DEBUG %s] ", time.Now().Format("15:04:05.000"))
-		fmt.Printf(format, args...)
-		fmt.Println()
+		fmt.Fprintf(os.Stderr, "[DEBUG %s] ", time.Now().Format("15:04:05.000"))
+		fmt.Fprintf(os.Stderr, format, args...)
+		fmt.Fprintln(os.Stderr)
 	}
 }
 
@@ -218,6 +183,41 @@ func LastDayOfMonth(timeValue time.Time) int {
 	return time.Date(timeValue.Year(), timeValue.Month()+1, 0, 0, 0, 0, 0, timeValue.Location()).Day()
 }
 
+// ErrInvalidDate is returned for dates that are neither a day nor a time in RFC 3339 format
+var ErrInvalidDate = errors.New("invalid date")
+
+// ParseDate parses a day such as 2025-03-31 in the local time zone or a time in RFC 3339 format
+// such as 2025-03-31T18:00:00Z and returns the period it covers. For a day the period spans the
+// whole day, for a time it starts and ends at that time.
+func ParseDate(value string) (start, end time.Time, err error) {
+	if day, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return day, day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	if moment, err := time.Parse(time.RFC3339, value); err == nil {
+		return moment, moment, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w %q", ErrInvalidDate, value)
+}
+
+// ErrInvalidSize is returned for sizes that are neither a number of bytes nor a number with a unit
+var ErrInvalidSize = errors.New("invalid size")
+
+// sizeUnits holds the decimal (SI) units FormatSize uses and their number of bytes
+var sizeUnits = map[string]float64{"": 1, "B": 1, "KB": 1000, "MB": 1000 * 1000, "GB": 1000 * 1000 * 1000, "TB": 1000 * 1000 * 1000 * 1000}
+
+// ParseSize parses a size such as 500MB, 1.5 GB or 1000000 (bytes) in the decimal units of FormatSize
+func ParseSize(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	number := strings.TrimRight(trimmed, "BKMGTbkmgt ")
+	unit, exists := sizeUnits[strings.ToUpper(strings.TrimSpace(trimmed[len(number):]))]
+	amount, err := strconv.ParseFloat(number, 64)
+	// ParseFloat accepts NaN and Inf, and sizes beyond int64 have no defined conversion
+	if !exists || err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 || amount*unit >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidSize, value)
+	}
+	return int64(amount * unit), nil
+}
+
 // GetChipInfo returns information about the CPU
 func GetChipInformation() string {
 	if runtime.GOOS == "darwin" {
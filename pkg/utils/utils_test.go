@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -294,6 +295,49 @@ func TestCalculateYearsMonthsDays(t *testing.T) {
 	}
 }
 
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "Day",
+			value:     "2025-03-31",
+			wantStart: time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2025, 3, 31, 23, 59, 59, 0, time.Local),
+		},
+		{
+			name:      "Time",
+			value:     "2025-03-31T18:00:00+02:00",
+			wantStart: time.Date(2025, 3, 31, 16, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 3, 31, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Invalid",
+			value:   "31.03.2025",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseDate(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Errorf("ParseDate(%q) error = %v, want ErrInvalidDate", tt.value, err)
+				}
+				return
+			}
+			if err != nil || !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("ParseDate(%q) = %v, %v, %v; want %v, %v", tt.value, start, end, err, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
 func TestIsTerminal(t *testing.T) {
 	// Test case 1: Pipes are not terminals
 	reader, writer, err := os.Pipe()
@@ -406,3 +450,68 @@ func TestGetTerminalInformationFallsBackToUnknown(t *testing.T) {
 	}
 }
 
+func TestWorkerPoolWithOneWorkerRunsWhenWaiting(t *testing.T) {
+	var order []string
+	pool := NewWorkerPool(1)
+	wait := pool.Go(func() { order = append(order, "background") })
+	order = append(order, "caller")
+	wait()
+	wait()
+
+	if strings.Join(order, ",") != "caller,background" {
+		t.Errorf("expected the function to run once when waited for, got %v", order)
+	}
+}
+
+func TestWorkerPoolLimitsConcurrentFunctions(t *testing.T) {
+	pool := NewWorkerPool(3) // The caller and two background workers
+	running := make(chan struct{}, 10)
+	release := make(chan struct{})
+	var waits []func()
+	for i := 0; i < 5; i++ {
+		waits = append(waits, pool.Go(func() {
+			running <- struct{}{}
+			<-release
+		}))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if started := len(running); started != 2 {
+		t.Errorf("expected 2 functions to run at the same time, got %d", started)
+	}
+	close(release)
+	for _, wait := range waits {
+		wait()
+	}
+	if started := len(running); started != 5 {
+		t.Errorf("expected all 5 functions to run, got %d", started)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "1000000", want: 1000000},
+		{value: "500MB", want: 500 * 1000 * 1000},
+		{value: "1.5 GB", want: 1500 * 1000 * 1000},
+		{value: "2kb", want: 2000},
+		{value: "GB", wantErr: true},
+		{value: "5 GiB", wantErr: true},
+		{value: "-1MB", wantErr: true},
+		{value: "NaN", wantErr: true},
+		{value: "Inf", wantErr: true},
+		{value: "+InfGB", wantErr: true},
+		{value: "-Inf", wantErr: true},
+		{value: "1e30 GB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSize(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+// WorkerPool limits how many functions run at the same time. The goroutine that waits for the
+// results counts as one of the workers, so a pool of one worker runs everything sequentially.
+type WorkerPool struct {
+	slots chan struct{}
+}
+
+// NewWorkerPool returns a pool that runs up to workers functions at the same time
+func NewWorkerPool(workers int) *WorkerPool {
+	return &WorkerPool{slots: make(chan struct{}, max(workers-1, 0))}
+}
+
+// Go runs function in the background as soon as a worker is free and returns a function that
+// waits until it has finished. Without a free worker other than the caller, which is always the
+// case for a nil pool or a pool of one worker, function runs when wait is called, so the order
+// of execution matches a sequential run. Functions must not wait for other functions of the pool.
+func (pool *WorkerPool) Go(function func()) (wait func()) {
+	if pool == nil || cap(pool.slots) == 0 {
+		finished := false
+		return func() {
+			if !finished {
+				finished = true
+				function()
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pool.slots <- struct{}{}
+		defer func() { <-pool.slots }()
+		function()
+	}()
+	return func() { <-done }
+}